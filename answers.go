@@ -0,0 +1,307 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	stdhtml "html"
+
+	"github.com/julienschmidt/httprouter"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	htmlEntity = regexp.MustCompile(`&#?[a-zA-Z0-9]+;`)
+	htmlTag    = regexp.MustCompile(`<[^>]*>`)
+	digitGroup = regexp.MustCompile(`(\d),(\d{3})\b`)
+
+	articles = map[string]bool{
+		"a":   true,
+		"an":  true,
+		"the": true,
+	}
+
+	units = map[string]int{
+		"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4,
+		"five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+		"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14,
+		"fifteen": 15, "sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+		"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+		"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	}
+
+	scales = map[string]int{
+		"hundred":  100,
+		"thousand": 1000,
+		"million":  1000000,
+		"billion":  1000000000,
+	}
+)
+
+type Guess struct {
+	Guess string `json:"guess"`
+
+	// Choice is the position of the picked option in a room's list of multiple-choice options
+	Choice *int `json:"choice,omitempty"`
+}
+
+type Verdict struct {
+	Correct     bool   `json:"correct"`
+	Answer      string `json:"answer,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+	Source      string `json:"source,omitempty"`
+}
+
+// splitAnswer separates a semicolon-delimited list of accepted answers into
+// the primary answer and its alternatives, ignoring the semicolons that
+// terminate HTML entities when --html is enabled
+func splitAnswer(answer string) (string, []string) {
+	protected := map[int]bool{}
+
+	if html {
+		for _, match := range htmlEntity.FindAllStringIndex(answer, -1) {
+			protected[match[1]-1] = true
+		}
+	}
+
+	parts := []string{}
+
+	start := 0
+
+	for i := 0; i <= len(answer); i++ {
+		if i < len(answer) && (answer[i] != ';' || protected[i]) {
+			continue
+		}
+
+		part := strings.TrimSpace(answer[start:i])
+		if part != "" {
+			parts = append(parts, part)
+		}
+
+		start = i + 1
+	}
+
+	if len(parts) < 1 {
+		return "", nil
+	}
+
+	return parts[0], parts[1:]
+}
+
+// normalizeAnswer reduces an answer to a canonical form by folding case and
+// diacritics, stripping punctuation and articles, and converting number words to digits
+func normalizeAnswer(answer string) string {
+	if html || markdown {
+		answer = stdhtml.UnescapeString(htmlTag.ReplaceAllString(answer, " "))
+	}
+
+	answer, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), answer)
+	if err != nil {
+		return ""
+	}
+
+	// Matches cannot overlap, so each pass removes every other separator
+	for digitGroup.MatchString(answer) {
+		answer = digitGroup.ReplaceAllString(answer, "$1$2")
+	}
+
+	answer = strings.ToLower(answer)
+
+	answer = strings.NewReplacer("&", " and ", "'", "", "’", "").Replace(answer)
+
+	answer = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return ' '
+	}, answer)
+
+	words := []string{}
+
+	for _, word := range strings.Fields(answer) {
+		if !articles[word] {
+			words = append(words, word)
+		}
+	}
+
+	// Answers such as "The The" consist only of articles, which are then kept
+	if len(words) < 1 {
+		words = strings.Fields(answer)
+	}
+
+	return strings.Join(convertNumbers(words), " ")
+}
+
+// convertNumbers replaces each run of number words (e.g. "twenty one") with its value
+func convertNumbers(words []string) []string {
+	converted := make([]string, 0, len(words))
+
+	total, current, inNumber := 0, 0, false
+
+	flush := func() {
+		if inNumber {
+			converted = append(converted, strconv.Itoa(total+current))
+		}
+
+		total, current, inNumber = 0, 0, false
+	}
+
+	for _, word := range words {
+		if value, exists := units[word]; exists {
+			current += value
+			inNumber = true
+
+			continue
+		}
+
+		if scale, exists := scales[word]; exists && inNumber {
+			current *= scale
+
+			if scale >= 1000 {
+				total += current
+				current = 0
+			}
+
+			continue
+		}
+
+		flush()
+
+		converted = append(converted, word)
+	}
+
+	flush()
+
+	return converted
+}
+
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(t)]
+}
+
+func numbers(answer string) string {
+	return strings.Join(strings.FieldsFunc(answer, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	}), " ")
+}
+
+// isMatch reports whether a normalized guess is close enough to a normalized answer,
+// allowing one typo per five characters but requiring any numbers to match exactly
+func isMatch(answer, guess string) bool {
+	if answer == guess {
+		return true
+	}
+
+	if numbers(answer) != numbers(guess) {
+		return false
+	}
+
+	return levenshtein(answer, guess) <= len([]rune(answer))/5
+}
+
+// checkAnswer reports whether a guess matches the answer or one of its alternatives. Guesses at
+// multiple-choice questions must match exactly, as an incorrect choice may be a near miss.
+func checkAnswer(t *Trivia, guess string) bool {
+	normalized := normalizeAnswer(guess)
+	if normalized == "" {
+		return false
+	}
+
+	exact := len(t.Incorrect) > 0
+
+	for _, answer := range append([]string{t.Answer}, t.Alternatives...) {
+		answer = normalizeAnswer(answer)
+
+		if answer == normalized || !exact && isMatch(answer, normalized) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func serveCheck(questions *Questions, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		t := questions.getTrivia(QuestionId(p.ByName("id")))
+		if t == nil {
+			metrics.questionNotFound()
+
+			writeJson(w, http.StatusNotFound, ApiError{"no question found"}, errorChannel)
+
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, 64*1024))
+		if err != nil {
+			errorChannel <- err
+
+			return
+		}
+
+		var guess Guess
+
+		err = json.Unmarshal(data, &guess)
+		if err != nil {
+			writeJson(w, http.StatusBadRequest, ApiError{"invalid guess"}, errorChannel)
+
+			return
+		}
+
+		verdict := Verdict{
+			Correct:     checkAnswer(t, guess.Guess),
+			Answer:      t.displayedAnswer(),
+			Explanation: t.Explanation,
+			Source:      t.Source,
+		}
+
+		if !html && !markdown {
+			verdict.Answer = template.HTMLEscapeString(t.displayedAnswer())
+			verdict.Explanation = template.HTMLEscapeString(t.Explanation)
+		}
+
+		writeJson(w, http.StatusOK, verdict, errorChannel)
+
+		slog.Debug("Checked answer",
+			"id", p.ByName("id"),
+			"remote", realIP(r),
+			"correct", verdict.Correct)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import "testing"
+
+func TestCheckAnswer(t *testing.T) {
+	tests := []struct {
+		name   string
+		trivia Trivia
+		guess  string
+		want   bool
+	}{
+		{"exact", Trivia{Answer: "Michael Jackson"}, "michael jackson", true},
+		{"typo", Trivia{Answer: "Michael Jackson"}, "Michael Jakson", true},
+		{"too many typos", Trivia{Answer: "Michael Jackson"}, "Michelle Johnston", false},
+		{"alternative", Trivia{Answer: "Michael Jackson", Alternatives: []string{"MJ"}}, "mj", true},
+		{"choice", Trivia{Answer: "Michael Jackson", Incorrect: []string{"Michael Johnson"}}, "Michael Jackson", true},
+		{"near miss choice", Trivia{Answer: "Michael Jackson", Incorrect: []string{"Michael Johnson"}}, "Michael Johnson", false},
+		{"empty", Trivia{Answer: "Michael Jackson"}, "", false},
+		{"thousands separators", Trivia{Answer: "1,000,000"}, "one million", true},
+		{"wrong number", Trivia{Answer: "1,000,000"}, "100000", false},
+		{"only articles", Trivia{Answer: "The The"}, "the the", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := checkAnswer(&test.trivia, test.guess); got != test.want {
+				t.Errorf("checkAnswer(%q, %q) = %v, want %v", test.trivia.Answer, test.guess, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAnswer(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"The Beatles", "beatles"},
+		{"Crème brûlée", "creme brulee"},
+		{"Twenty-one", "21"},
+		{"1,000", "1000"},
+		{"1,000,000", "1000000"},
+		{"one million", "1000000"},
+		{"1,2,3", "1 2 3"},
+		{"The The", "the the"},
+		{"A", "a"},
+	}
+
+	for _, test := range tests {
+		if got := normalizeAnswer(test.answer); got != test.want {
+			t.Errorf("normalizeAnswer(%q) = %q, want %q", test.answer, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ApiQuestion struct {
+	Id           QuestionId `json:"id"`
+	Question     string     `json:"question"`
+	Answer       string     `json:"answer,omitempty"`
+	Alternatives []string   `json:"alternatives,omitempty"`
+	Category     Category   `json:"category"`
+	Incorrect    []string   `json:"incorrect,omitempty"`
+	Choices      []string   `json:"choices,omitempty"`
+	Difficulty   Difficulty `json:"difficulty,omitempty"`
+	Media        *Media     `json:"media,omitempty"`
+	Explanation  string     `json:"explanation,omitempty"`
+	Source       string     `json:"source,omitempty"`
+	Color        string     `json:"color"`
+	Abbreviation string     `json:"abbreviation,omitempty"`
+}
+
+type ApiCategory struct {
+	Name         Category `json:"name"`
+	Parent       Category `json:"parent,omitempty"`
+	Color        string   `json:"color"`
+	Abbreviation string   `json:"abbreviation,omitempty"`
+	Questions    int      `json:"questions"`
+	Enabled      bool     `json:"enabled"`
+	Weight       float64  `json:"weight"`
+}
+
+type ApiError struct {
+	Error string `json:"error"`
+}
+
+func newApiQuestion(id QuestionId, t *Trivia, color Color) ApiQuestion {
+	q := ApiQuestion{
+		Id:           id,
+		Question:     t.Question,
+		Answer:       t.Answer,
+		Alternatives: t.Alternatives,
+		Category:     t.Category,
+		Incorrect:    t.Incorrect,
+		Difficulty:   t.Difficulty,
+		Media:        t.attachment,
+		Explanation:  t.Explanation,
+		Source:       t.Source,
+		Color:        color.Hex,
+		Abbreviation: color.Abbreviation,
+	}
+
+	// Answers are checked by the server, so only the shuffled multiple-choice options are included
+	if checkAnswers {
+		q.Answer, q.Alternatives, q.Incorrect, q.Explanation, q.Source = "", nil, nil, "", ""
+
+		if len(t.Incorrect) > 0 {
+			q.Choices = append([]string{t.Answer}, t.Incorrect...)
+
+			rand.Shuffle(len(q.Choices), func(i, j int) {
+				q.Choices[i], q.Choices[j] = q.Choices[j], q.Choices[i]
+			})
+		}
+	}
+
+	return q
+}
+
+func writeJson(w http.ResponseWriter, status int, v any, errorChannel chan<- error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		errorChannel <- err
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+
+	w.Header().Set("Content-Security-Policy", "default-src 'none';")
+
+	securityHeaders(w)
+
+	w.WriteHeader(status)
+
+	_, err = w.Write(append(data, '\n'))
+	if err != nil {
+		errorChannel <- err
+	}
+}
+
+func serveApiRandom(questions *Questions, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		id := questions.getRandomId(r)
+
+		t := questions.getTrivia(id)
+		if t == nil {
+			writeJson(w, http.StatusNotFound, ApiError{"no questions available"}, errorChannel)
+
+			return
+		}
+
+		metrics.questionServed(t.Category)
+
+		writeJson(w, http.StatusOK, newApiQuestion(id, t, questions.getColor(t.Category)), errorChannel)
+	}
+}
+
+func serveApiQuestion(questions *Questions, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		id := QuestionId(p.ByName("id"))
+
+		t := questions.getTrivia(id)
+		if t == nil {
+			metrics.questionNotFound()
+
+			writeJson(w, http.StatusNotFound, ApiError{fmt.Sprintf("no question found with id %s", id)}, errorChannel)
+
+			return
+		}
+
+		metrics.questionServed(t.Category)
+
+		writeJson(w, http.StatusOK, newApiQuestion(id, t, questions.getColor(t.Category)), errorChannel)
+	}
+}
+
+func serveApiCategories(questions *Questions, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		enabled := getCategories(r, questions)
+		weights := getWeights(r)
+
+		categories := []ApiCategory{}
+
+		questions.mu.RLock()
+		for category, ids := range questions.index {
+			color := getColor(questions.colors, category)
+
+			categories = append(categories, ApiCategory{
+				Name:         category,
+				Parent:       category.parent(),
+				Color:        color.Hex,
+				Abbreviation: color.Abbreviation,
+				Questions:    len(ids),
+				Enabled:      slices.Contains(enabled, category.String()),
+				Weight:       questions.getWeight(category, weights),
+			})
+		}
+		questions.mu.RUnlock()
+
+		slices.SortFunc(categories, func(a, b ApiCategory) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		writeJson(w, http.StatusOK, categories, errorChannel)
+	}
+}
+
+func registerApi(mux *Router, questions *Questions, metrics *Metrics, errorChannel chan<- error) {
+	mux.GET("/api/v1/random", serveApiRandom(questions, metrics, errorChannel))
+	mux.GET("/api/v1/questions/:id", serveApiQuestion(questions, metrics, errorChannel))
+	mux.GET("/api/v1/categories", serveApiCategories(questions, errorChannel))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Auth protects administrative routes, accepting any one of
+// the configured bearer tokens, htpasswd users, or client certificates
+type Auth struct {
+	tokens [][]byte
+
+	// Users is a mapping of usernames to password hashes
+	users map[string]string
+
+	clientCerts bool
+}
+
+// newAuth builds the authentication configuration from the command-line flags,
+// returning nil if no authentication method has been configured
+func newAuth() (*Auth, error) {
+	if len(adminTokens) == 0 && adminHtpasswd == "" && adminCa == "" {
+		return nil, nil
+	}
+
+	auth := &Auth{
+		users:       map[string]string{},
+		clientCerts: adminCa != "",
+	}
+
+	for _, token := range adminTokens {
+		if token != "" {
+			auth.tokens = append(auth.tokens, []byte(token))
+		}
+	}
+
+	if adminHtpasswd != "" {
+		err := auth.loadHtpasswd(adminHtpasswd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auth, nil
+}
+
+// loadHtpasswd reads users from an htpasswd-style file,
+// accepting bcrypt and {SHA} password hashes
+func (a *Auth) loadHtpasswd(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+
+	l := 0
+
+	for s.Scan() {
+		l += 1
+
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		user, hash, found := strings.Cut(line, ":")
+		if !found || user == "" {
+			return fmt.Errorf("invalid htpasswd entry at %s:%d", path, l)
+		}
+
+		if !strings.HasPrefix(hash, "$2") && !strings.HasPrefix(hash, "{SHA}") {
+			return fmt.Errorf("unsupported password hash for user %s at %s:%d (only bcrypt and {SHA} are supported)", user, path, l)
+		}
+
+		a.users[user] = hash
+	}
+
+	return s.Err()
+}
+
+func (a *Auth) checkPassword(user, password string) bool {
+	hash, exists := a.users[user]
+	if !exists {
+		return false
+	}
+
+	if encoded, found := strings.CutPrefix(hash, "{SHA}"); found {
+		sum := sha1.Sum([]byte(password))
+
+		return subtle.ConstantTimeCompare([]byte(encoded), []byte(base64.StdEncoding.EncodeToString(sum[:]))) == 1
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (a *Auth) isAuthorized(r *http.Request) bool {
+	if a.clientCerts && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		for _, t := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+				return true
+			}
+		}
+	}
+
+	if user, password, ok := r.BasicAuth(); ok {
+		return a.checkPassword(user, password)
+	}
+
+	return false
+}
+
+func (a *Auth) unauthorized(w http.ResponseWriter, r *http.Request) {
+	slog.Warn("Rejected unauthorized request",
+		"uri", r.RequestURI,
+		"remote", realIP(r))
+
+	if len(a.users) > 0 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="trivia", charset="UTF-8"`)
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="trivia"`)
+	}
+
+	securityHeaders(w)
+
+	http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+}
+
+// protect wraps a handler so that it is only served to authorized requests
+func (a *Auth) protect(h httprouter.Handle) httprouter.Handle {
+	if a == nil {
+		return h
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		if !a.isAuthorized(r) {
+			a.unauthorized(w, r)
+
+			return
+		}
+
+		h(w, r, p)
+	}
+}
+
+// protectHandler is the equivalent of protect for standard library handlers
+func (a *Auth) protectHandler(h http.Handler) http.Handler {
+	if a == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.isAuthorized(r) {
+			a.unauthorized(w, r)
+
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// tlsConfig requests, but does not require, client certificates signed by
+// the configured certificate authorities, so that only protected routes need them
+func tlsConfig() (*tls.Config, error) {
+	if adminCa == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(adminCa)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no valid certificates found in admin CA file")
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  pool,
+	}, nil
+}
@@ -6,7 +6,8 @@ package main
 
 import (
 	"net/http"
-	"strings"
+	"net/url"
+	"slices"
 )
 
 func setCookie(name, value string, w http.ResponseWriter) {
@@ -23,13 +24,83 @@ func setCookie(name, value string, w http.ResponseWriter) {
 	http.SetCookie(w, &cookie)
 }
 
+// getCategories returns the enabled categories, including the descendants of any enabled parent categories
 func getCategories(r *http.Request, questions *Questions) []string {
+	query := getQuery(r, "categories")
+	if query != "" {
+		return questions.expand(query)
+	}
+
 	cookie := getCookie(r, "enabledCategories")
 	if cookie == "" || !settings {
 		return questions.CategoryStrings()
 	}
 
-	return strings.Split(cookie, ",")
+	return questions.expand(cookie)
+}
+
+// getDifficulties returns the enabled difficulties, where the empty set enables every difficulty
+// and a list naming no known difficulty enables none
+func getDifficulties(r *http.Request) DifficultySet {
+	query := getQuery(r, "difficulties")
+	if query != "" {
+		return parseDifficultySet(query)
+	}
+
+	if !settings {
+		return 0
+	}
+
+	return parseDifficultySet(getCookie(r, "enabledDifficulties"))
+}
+
+// getSelection returns the selection mode, preferring the selection query parameter
+// and selectionMode cookie to the --selection flag
+func getSelection(r *http.Request) string {
+	mode := getQuery(r, "selection")
+
+	if mode == "" && settings {
+		mode = getCookie(r, "selectionMode")
+	}
+
+	if !slices.Contains(selectionModes, mode) {
+		return selection
+	}
+
+	return mode
+}
+
+// getWeights returns the category weights set via the settings page, which are
+// stored in the categoryWeights cookie as a URL-encoded query string
+func getWeights(r *http.Request) map[string]float64 {
+	weights := map[string]float64{}
+
+	if !settings {
+		return weights
+	}
+
+	values, err := url.ParseQuery(getCookie(r, "categoryWeights"))
+	if err != nil {
+		return weights
+	}
+
+	for category := range values {
+		weight, valid := parseWeight(values.Get(category))
+		if valid {
+			weights[category] = weight
+		}
+	}
+
+	return weights
+}
+
+// getQuery returns the value of a query parameter, without parsing the query if there is none
+func getQuery(r *http.Request, key string) string {
+	if r.URL.RawQuery == "" {
+		return ""
+	}
+
+	return r.URL.Query().Get(key)
 }
 
 func getCookie(r *http.Request, name string) string {
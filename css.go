@@ -43,7 +43,7 @@ func serveCss(errorChannel chan<- error) httprouter.Handle {
 	}
 }
 
-func registerCss(mux *httprouter.Router, errorChannel chan<- error) {
+func registerCss(mux *Router, errorChannel chan<- error) {
 	mime.AddExtensionType(".css", "text/css; charset=utf-8")
 
 	mux.GET("/css/:css", serveCss(errorChannel))
@@ -5,47 +5,268 @@ Copyright © 2026 Seednode <seednode@seedno.de>
 package main
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"time"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+// exportFormats is a mapping of the supported values of the format parameter to their content types
+var exportFormats = map[string]string{
+	"trivia": "text/plain;charset=UTF-8",
+	"json":   "application/json;charset=UTF-8",
+	"csv":    "text/csv;charset=UTF-8",
+	"text":   "text/plain;charset=UTF-8",
+}
+
+// getExportFormat returns the format requested via the format parameter,
+// falling back to the Accept header and then the human-readable text format
+func getExportFormat(r *http.Request) string {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if _, exists := exportFormats[format]; exists {
+		return format
+	}
+
+	accept := r.Header.Get("Accept")
+
+	switch {
+	case strings.Contains(accept, "application/json"):
+		return "json"
+	case strings.Contains(accept, "text/csv"):
+		return "csv"
+	case strings.Contains(accept, "text/plain"):
+		return "trivia"
+	default:
+		return "text"
+	}
+}
+
+// getExported returns the questions in the given categories and their descendants, or in every category if none are given,
+// as they were written and sorted by category, question, and answer so that exports are deterministic
+func (q *Questions) getExported(categories []string) []*Trivia {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	exported := []*Trivia{}
+
+	for category, ids := range q.index {
+		if len(categories) > 0 && !slices.ContainsFunc(categories, func(c string) bool {
+			return Category(c).contains(category)
+		}) {
+			continue
+		}
+
+		for _, id := range ids {
+			exported = append(exported, q.list[id].written())
+		}
+	}
+
+	slices.SortFunc(exported, func(a, b *Trivia) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(a.Question, b.Question), cmp.Compare(a.Answer, b.Answer), cmp.Compare(a.getId(), b.getId()))
+	})
+
+	return exported
+}
+
+func toRecord(t *Trivia) record {
+	return record{
+		Question:     t.Question,
+		Answer:       t.Answer,
+		Alternatives: t.Alternatives,
+		Category:     t.Category.String(),
+		Incorrect:    t.Incorrect,
+		Difficulty:   string(t.Difficulty),
+		Media:        t.Media,
+		Explanation:  t.Explanation,
+		Source:       t.Source,
+	}
+}
+
+// canDelimit reports whether the lists of a question survive being joined with
+// semicolons and split again, as required by the native and CSV formats
+func canDelimit(t *Trivia) bool {
+	answer, alternatives := splitAnswer(strings.Join(append([]string{t.Answer}, t.Alternatives...), ";"))
+
+	return answer == t.Answer &&
+		slices.Equal(alternatives, t.Alternatives) &&
+		slices.Equal(splitList(strings.Join(t.Incorrect, ";")), t.Incorrect)
+}
+
+// exportTrivia writes the questions in the native format, returning the number which were skipped
+func exportTrivia(b *bytes.Buffer, exported []*Trivia) int {
+	skipped := 0
+
+	for _, t := range exported {
+		fields := []string{t.Question, strings.Join(append([]string{t.Answer}, t.Alternatives...), ";"), t.Category.String()}
+
+		if len(t.Incorrect) > 0 {
+			fields = append(fields, "incorrect="+strings.Join(t.Incorrect, ";"))
+		}
+
+		if t.Difficulty != Unrated {
+			fields = append(fields, "difficulty="+t.Difficulty.String())
+		}
+
+		if t.Media != "" {
+			fields = append(fields, "media="+t.Media)
+		}
+
+		if t.Explanation != "" {
+			fields = append(fields, "explanation="+t.Explanation)
+		}
+
+		if t.Source != "" {
+			fields = append(fields, "source="+t.Source)
+		}
+
+		line := strings.Join(fields, "|")
+
+		// Questions which were loaded from structured formats may contain
+		// characters with special meaning in the native format
+		if strings.Count(line, "|") != len(fields)-1 || strings.ContainsAny(line, "\r\n") || !canDelimit(t) {
+			slog.Warn("Skipped question which cannot be represented in the native format",
+				"id", t.getId())
+
+			skipped++
+
+			continue
+		}
+
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	return skipped
+}
+
+// exportCsv writes the questions in the CSV format, returning the number which were skipped
+func exportCsv(b *bytes.Buffer, exported []*Trivia) (int, error) {
+	w := csv.NewWriter(b)
+
+	skipped := 0
+
+	err := w.Write([]string{"question", "answer", "alternatives", "category", "incorrect", "difficulty", "media", "explanation", "source"})
+	if err != nil {
+		return skipped, err
+	}
+
+	for _, t := range exported {
+		if !canDelimit(t) {
+			slog.Warn("Skipped question which cannot be represented in the CSV format",
+				"id", t.getId())
+
+			skipped++
+
+			continue
+		}
+
+		err = w.Write([]string{
+			t.Question,
+			t.Answer,
+			strings.Join(t.Alternatives, ";"),
+			t.Category.String(),
+			strings.Join(t.Incorrect, ";"),
+			string(t.Difficulty),
+			t.Media,
+			t.Explanation,
+			t.Source,
+		})
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	w.Flush()
+
+	return skipped, w.Error()
+}
+
+func exportJson(b *bytes.Buffer, exported []*Trivia) error {
+	records := make([]record, 0, len(exported))
+
+	for _, t := range exported {
+		records = append(records, toRecord(t))
+	}
+
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	return e.Encode(records)
+}
+
+// exportText writes the original human-readable format, which cannot be loaded back in
+func exportText(b *bytes.Buffer, exported []*Trivia) {
+	for _, t := range exported {
+		fmt.Fprintf(b, "Category: %s\nQuestion: %s\nAnswer: %s\n\n", t.Category, t.Question, t.displayedAnswer())
+	}
+}
+
 func serveExport(questions *Questions, errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
+		format := getExportFormat(r)
 
-		w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+		contentType := exportFormats[format]
 
-		w.Header().Add("Content-Security-Policy", "default-src 'self';")
+		var categories []string
 
-		securityHeaders(w)
+		if c := r.URL.Query().Get("categories"); c != "" {
+			categories = strings.Split(c, ",")
+		}
+
+		exported := questions.getExported(categories)
+
+		var b bytes.Buffer
+
+		var err error
 
-		if verbose {
-			fmt.Printf("%s | %s => %s\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI)
+		skipped := 0
+
+		switch format {
+		case "trivia":
+			skipped = exportTrivia(&b, exported)
+		case "json":
+			err = exportJson(&b, exported)
+		case "csv":
+			skipped, err = exportCsv(&b, exported)
+		case "text":
+			exportText(&b, exported)
+		}
+
+		if err != nil {
+			errorChannel <- err
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
 		}
 
-		questions.mu.RLock()
-		defer questions.mu.RUnlock()
+		w.Header().Set("Content-Type", contentType)
+
+		// Skipped questions are reported, so that callers can tell whether the export is complete
+		w.Header().Set("X-Skipped-Questions", strconv.Itoa(skipped))
+
+		w.Header().Add("Content-Security-Policy", "default-src 'self';")
+
+		securityHeaders(w)
 
-		for i := range questions.index {
-			for j := range questions.index[i] {
-				entry := questions.list[questions.index[i][j]]
-				_, err := w.Write(fmt.Appendf(nil, "Category: %s\nQuestion: %s\nAnswer: %s\n\n", entry.Category, entry.Question, entry.Answer))
-				if err != nil {
-					errorChannel <- err
+		_, err = w.Write(b.Bytes())
+		if err != nil {
+			errorChannel <- err
 
-					return
-				}
-			}
+			return
 		}
 	}
 }
 
-func registerExport(mux *httprouter.Router, questions *Questions, errorChannel chan<- error) {
-	mux.GET("/export", serveExport(questions, errorChannel))
+func registerExport(mux *Router, auth *Auth, questions *Questions, errorChannel chan<- error) {
+	mux.GET("/export", auth.protect(serveExport(questions, errorChannel)))
 }
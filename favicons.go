@@ -40,7 +40,7 @@ func serveFavicons(errorChannel chan<- error) httprouter.Handle {
 	}
 }
 
-func registerFavicons(mux *httprouter.Router, errorChannel chan<- error) {
+func registerFavicons(mux *Router, errorChannel chan<- error) {
 	mux.GET("/favicons/:favicon", serveFavicons(errorChannel))
 	mux.GET("/favicon.ico", serveFavicons(errorChannel))
 }
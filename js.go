@@ -43,7 +43,7 @@ func serveJs(errorChannel chan<- error) httprouter.Handle {
 	}
 }
 
-func registerJs(mux *httprouter.Router, errorChannel chan<- error) {
+func registerJs(mux *Router, errorChannel chan<- error) {
 	mime.AddExtensionType(".js", "application/javascript; charset=utf-8")
 
 	mux.GET("/js/:js", serveJs(errorChannel))
@@ -0,0 +1,72 @@
+function renderSource(element, source) {
+    element.replaceChildren();
+
+    if (!source) {
+        return;
+    }
+
+    element.append("Source: ");
+
+    if (/^https?:\/\/[^\/]/.test(source)) {
+        let link = document.createElement("a");
+        link.href = source;
+        link.textContent = source;
+        element.appendChild(link);
+    } else {
+        element.append(source);
+    }
+}
+
+function submitGuess(guess, choice) {
+    let xhr = new XMLHttpRequest();
+    xhr.open("POST", "/check/" + window.location.pathname.split("/").pop(), true);
+    xhr.setRequestHeader("Content-Type", "application/json");
+    xhr.onload = function () {
+        if (xhr.status !== 200) {
+            return;
+        }
+
+        let verdict = JSON.parse(xhr.responseText);
+
+        if (guess !== "") {
+            let result = document.getElementById("verdict");
+            result.textContent = verdict.correct ? "Correct!" : "Incorrect.";
+            result.className = verdict.correct ? "correct" : "incorrect";
+        }
+
+        if (choice) {
+            choice.classList.add(verdict.correct ? "correct" : "incorrect");
+        }
+
+        document.querySelectorAll('.choice, #guess, #submit-guess').forEach(function(element) {
+            element.disabled = true;
+        });
+
+        document.querySelector("#answer p").innerHTML = verdict.answer;
+        document.getElementById("explanation").innerHTML = verdict.explanation || "";
+        renderSource(document.getElementById("source"), verdict.source);
+        document.getElementById("answer").style.display = "block";
+    };
+    xhr.send(JSON.stringify({ guess: guess }));
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    let form = document.getElementById('guess-form');
+    if (form) {
+        form.addEventListener('submit', function (event) {
+            event.preventDefault();
+            submitGuess(document.getElementById("guess").value.trim(), null);
+        });
+    }
+
+    document.getElementById('toggle-answer')
+    .addEventListener('click', function () {
+        submitGuess("", null);
+    });
+
+    document.querySelectorAll('.choice').forEach(function(choice) {
+        choice.addEventListener('click', function (event) {
+            submitGuess(event.currentTarget.textContent, event.currentTarget);
+        });
+    });
+});
@@ -0,0 +1,27 @@
+function selectChoice(event) {
+    var choices = document.querySelectorAll('.choice');
+
+    choices.forEach(function(choice) {
+        choice.disabled = true;
+
+        if (choice.dataset.correct === "true") {
+            choice.classList.add('correct');
+        }
+    });
+
+    if (event.currentTarget.dataset.correct !== "true") {
+        event.currentTarget.classList.add('incorrect');
+    }
+
+    document.getElementById("answer").style.display = "block";
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    if (document.getElementById('verdict')) {
+        return;
+    }
+
+    document.querySelectorAll('.choice').forEach(function(choice) {
+        choice.addEventListener('click', selectChoice);
+    });
+});
@@ -0,0 +1,201 @@
+var code = document.body.dataset.code;
+var role = document.body.dataset.role;
+var round = -1;
+var deadline = null;
+var countdown = null;
+var media = null;
+
+function post(path, body, callback) {
+    let xhr = new XMLHttpRequest();
+    xhr.open("POST", "/rooms/" + code + "/" + path, true);
+    xhr.setRequestHeader("Content-Type", "application/json");
+    xhr.onload = function () {
+        if (callback) {
+            callback(xhr);
+        }
+    };
+    xhr.send(JSON.stringify(body));
+}
+
+function submitGuess(guess, choice) {
+    post("answers", { guess: guess, choice: choice }, function (xhr) {
+        if (xhr.status !== 200) {
+            return;
+        }
+
+        let verdict = JSON.parse(xhr.responseText);
+        let result = document.getElementById("verdict");
+        result.textContent = verdict.correct ? "Correct!" : "Incorrect.";
+        result.className = verdict.correct ? "correct" : "incorrect";
+    });
+}
+
+function tick() {
+    let timer = document.getElementById("timer");
+
+    if (deadline === null) {
+        timer.textContent = "";
+        return;
+    }
+
+    let remaining = Math.max(0, Math.ceil((deadline - Date.now()) / 1000));
+    timer.textContent = remaining + "s";
+
+    if (remaining === 0) {
+        clearInterval(countdown);
+        countdown = null;
+    }
+}
+
+function renderSource(element, source) {
+    element.replaceChildren();
+
+    if (!source) {
+        return;
+    }
+
+    element.append("Source: ");
+
+    if (/^https?:\/\/[^\/]/.test(source)) {
+        let link = document.createElement("a");
+        link.href = source;
+        link.textContent = source;
+        element.appendChild(link);
+    } else {
+        element.append(source);
+    }
+}
+
+function renderMedia(state) {
+    let url = state.media ? state.media.url : null;
+
+    // Media is only replaced when it changes, so that playback isn't interrupted by updates
+    if (url === media) {
+        return;
+    }
+
+    media = url;
+
+    let container = document.getElementById("media");
+    container.replaceChildren();
+
+    if (!state.media) {
+        return;
+    }
+
+    let kind = state.media.type.split("/")[0];
+    let element = document.createElement(kind === "image" ? "img" : kind);
+    element.src = state.media.url;
+
+    if (kind === "image") {
+        element.alt = "";
+    } else {
+        element.controls = true;
+    }
+
+    container.appendChild(element);
+}
+
+function render(state) {
+    let newRound = state.round !== round;
+    round = state.round;
+
+    document.getElementById("round").textContent = state.round > 0 ? "Round " + state.round : "Waiting for the host to start...";
+    document.getElementById("question").innerHTML = state.question || "";
+    renderMedia(state);
+
+    let footer = document.querySelector(".footer");
+    footer.style.backgroundColor = state.color || "";
+    document.getElementById("category").textContent = state.category ? state.category + (state.abbreviation ? " (" + state.abbreviation + ")" : "") : "";
+
+    let answered = state.closed || (state.you && state.you.answered);
+
+    if (countdown !== null) {
+        clearInterval(countdown);
+        countdown = null;
+    }
+
+    deadline = state.remaining ? Date.now() + state.remaining : null;
+    if (deadline !== null) {
+        countdown = setInterval(tick, 250);
+    }
+    tick();
+
+    if (state.closed && !state.revealed && state.question) {
+        document.getElementById("timer").textContent = "Time's up!";
+    }
+
+    if (newRound) {
+        let choices = document.getElementById("choices");
+        choices.replaceChildren();
+
+        (state.choices || []).forEach(function (choice, index) {
+            let button = document.createElement("button");
+            button.className = "choice";
+            button.innerHTML = choice;
+            if (role === "player") {
+                button.addEventListener("click", function () {
+                    button.classList.add("selected");
+                    submitGuess(button.textContent, index);
+                });
+            }
+            choices.appendChild(button);
+        });
+
+        if (role === "player") {
+            document.getElementById("guess").value = "";
+            document.getElementById("verdict").textContent = "";
+        }
+    }
+
+    if (role === "player") {
+        document.getElementById("guess-form").style.display = state.question && !(state.choices && state.choices.length) ? "" : "none";
+        document.querySelectorAll('.choice, #guess, #submit-guess').forEach(function (element) {
+            element.disabled = answered;
+        });
+    }
+
+    document.querySelector("#answer p").innerHTML = state.answer || "";
+    document.getElementById("explanation").innerHTML = state.explanation || "";
+    renderSource(document.getElementById("source"), state.source);
+    document.getElementById("answer").style.display = state.answer ? "block" : "none";
+
+    let scoreboard = document.getElementById("scoreboard");
+    scoreboard.replaceChildren();
+
+    state.players.forEach(function (player) {
+        let item = document.createElement("li");
+        item.textContent = player.nickname + ": " + player.score + (player.answered ? " ✓" : "");
+        if (player.correct !== undefined && player.answered) {
+            item.className = player.correct ? "correct" : "incorrect";
+        }
+        scoreboard.appendChild(item);
+    });
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    let events = new EventSource("/rooms/" + code + "/events" + (role === "display" ? "?role=display" : ""));
+    events.onmessage = function (event) {
+        render(JSON.parse(event.data));
+    };
+
+    if (role === "host") {
+        document.getElementById('next-question')
+        .addEventListener('click', function () {
+            post("next", { seconds: parseInt(document.getElementById("time-limit").value, 10) }, null);
+        });
+
+        document.getElementById('reveal-answer')
+        .addEventListener('click', function () {
+            post("reveal", {}, null);
+        });
+    }
+
+    if (role === "player") {
+        document.getElementById('guess-form')
+        .addEventListener('submit', function (event) {
+            event.preventDefault();
+            submitGuess(document.getElementById("guess").value.trim());
+        });
+    }
+});
@@ -1,11 +1,18 @@
 function setCategories() {
-    selected = document.querySelectorAll('input[type="checkbox"]:checked');
-    total = document.querySelectorAll('input[type="checkbox"]');
+    selected = document.querySelectorAll('#categories input[type="checkbox"]:checked');
+    total = document.querySelectorAll('#categories input[type="checkbox"]');
 
     let json = {
         categories: [],
+        weights: {},
     };
 
+    document.querySelectorAll('#categories input.category-weight').forEach(function(input) {
+        if (input.value !== "") {
+            json.weights[input.name] = Number(input.value);
+        }
+    })
+
     iter = selected.entries();
 
     let result = iter.next();
@@ -29,7 +36,7 @@ document.addEventListener('DOMContentLoaded', function () {
 });
 
 function setNone() {
-    document.querySelectorAll('input[type="checkbox"]').forEach(function(checkbox) {
+    document.querySelectorAll('#categories input[type="checkbox"]').forEach(function(checkbox) {
         checkbox.checked = false;
     })
 }
@@ -40,7 +47,7 @@ document.addEventListener('DOMContentLoaded', function () {
 });
 
 function setAll() {
-    document.querySelectorAll('input[type="checkbox"]').forEach(function(checkbox) {
+    document.querySelectorAll('#categories input[type="checkbox"]').forEach(function(checkbox) {
         checkbox.checked = true;
     })
 }
@@ -48,4 +55,27 @@ function setAll() {
 document.addEventListener('DOMContentLoaded', function () {
     document.getElementById('select-all')
     .addEventListener('click', setAll);
-});
\ No newline at end of file
+});
+// Selecting a category selects everything beneath it, and deselecting one deselects everything above it
+function cascadeCategory(event) {
+    let checkbox = event.target;
+    let item = checkbox.closest('li');
+
+    item.querySelectorAll('input[type="checkbox"]').forEach(function(descendant) {
+        descendant.checked = checkbox.checked;
+    })
+
+    if (!checkbox.checked) {
+        let parent = item.parentElement.closest('#categories li');
+        while (parent) {
+            parent.querySelector(':scope > input[type="checkbox"]').checked = false;
+            parent = parent.parentElement.closest('#categories li');
+        }
+    }
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    document.querySelectorAll('#categories input[type="checkbox"]').forEach(function(checkbox) {
+        checkbox.addEventListener('change', cascadeCategory);
+    })
+});
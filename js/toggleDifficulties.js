@@ -0,0 +1,30 @@
+function setDifficulties() {
+    selected = document.querySelectorAll('#difficulties input[type="checkbox"]:checked');
+    total = document.querySelectorAll('#difficulties input[type="checkbox"]');
+
+    if (selected.length === 0) {
+        alert("Select at least one difficulty.");
+        return;
+    }
+
+    let json = {
+        difficulties: [],
+    };
+
+    selected.forEach(function(checkbox) {
+        json.difficulties.push(checkbox.name);
+    })
+
+    let xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href + "/difficulties", true);
+    xhr.setRequestHeader("Content-Type", "application/json");
+    let data = JSON.stringify({ ...json });
+    xhr.send(data);
+
+    alert("Selected " + selected.length + " out of " + total.length + " difficulties.");
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    document.getElementById('set-difficulties')
+    .addEventListener('click', setDifficulties);
+});
@@ -0,0 +1,12 @@
+function setSelection() {
+    let xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href + "/selection/" + document.querySelector('input[name="selection"]:checked').value, true);
+    xhr.send("");
+
+    alert("Updated selection mode.");
+}
+
+document.addEventListener('DOMContentLoaded', function () {
+    document.getElementById('set-selection')
+    .addEventListener('click', setSelection);
+});
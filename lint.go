@@ -0,0 +1,249 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+
+	"github.com/spf13/cobra"
+)
+
+// Void elements never have a closing tag
+var voidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
+// A Problem is a single issue found in a question or colors file
+type Problem struct {
+	path    string
+	line    int
+	message string
+}
+
+func (p Problem) String() string {
+	if p.line == 0 {
+		return fmt.Sprintf("%s: %s", p.path, p.message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", p.path, p.line, p.message)
+}
+
+// checkHtml returns a description of the first unbalanced tag in the given text,
+// or an empty string if every tag is closed in the order it was opened
+func checkHtml(text string) string {
+	z := nethtml.NewTokenizer(strings.NewReader(text))
+
+	open := []string{}
+
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			if len(open) > 0 {
+				return fmt.Sprintf("unclosed tag <%s>", open[len(open)-1])
+			}
+
+			return ""
+		case nethtml.StartTagToken:
+			name, _ := z.TagName()
+
+			if !voidElements[string(name)] {
+				open = append(open, string(name))
+			}
+		case nethtml.EndTagToken:
+			name, _ := z.TagName()
+
+			if voidElements[string(name)] {
+				continue
+			}
+
+			if len(open) < 1 || open[len(open)-1] != string(name) {
+				return fmt.Sprintf("unexpected closing tag </%s>", name)
+			}
+
+			open = open[:len(open)-1]
+		}
+	}
+}
+
+// lintFile checks the entries of a single question file, returning the valid entries
+// so that duplicates and categories can be checked across every file
+func lintFile(path string) ([]entry, []Problem) {
+	problems := []Problem{}
+
+	skip := func(line int, reason string) {
+		problems = append(problems, Problem{path, line, reason})
+	}
+
+	strip := func(line int, field string, removed []string) {
+		problems = append(problems, Problem{path, line, fmt.Sprintf("disallowed HTML in %s: %s", field, strings.Join(removed, ", "))})
+	}
+
+	entries, err := parseFile(path, skip, strip)
+	if err != nil {
+		problems = append(problems, Problem{path: path, message: strings.TrimPrefix(err.Error(), path+": ")})
+	}
+
+	if !html {
+		return entries, problems
+	}
+
+	for _, e := range entries {
+		t := e.trivia
+
+		fields := map[string][]string{
+			"question":     {t.Question},
+			"answer":       {t.Answer},
+			"alternatives": t.Alternatives,
+			"incorrect":    t.Incorrect,
+			"explanation":  {t.Explanation},
+		}
+
+		for _, field := range []string{"question", "answer", "alternatives", "incorrect", "explanation"} {
+			for _, text := range fields[field] {
+				if reason := checkHtml(text); reason != "" {
+					problems = append(problems, Problem{path, e.line, fmt.Sprintf("%s in %s", reason, field)})
+
+					break
+				}
+			}
+		}
+	}
+
+	return entries, problems
+}
+
+// lint checks every question file under the provided paths,
+// returning every problem found, ordered by file and line
+func lint(args []string) ([]Problem, error) {
+	paths, err := validatePaths(args)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []error{}
+
+	errorChannel := make(chan error)
+	done := make(chan struct{})
+
+	go func() {
+		for err := range errorChannel {
+			errs = append(errs, err)
+		}
+
+		close(done)
+	}()
+
+	found := map[string]os.FileInfo{}
+
+	for _, path := range paths {
+		walkPath(path, found, errorChannel)
+	}
+
+	close(errorChannel)
+	<-done
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	problems := []Problem{}
+
+	var colors map[Category]Color
+
+	if colorsFile != "" {
+		var rejected []Problem
+
+		colors, rejected, err = loadColors(colorsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, rejected...)
+	}
+
+	// Duplicates are reported against the first occurrence, in file and line order
+	seen := map[QuestionId]string{}
+
+	for _, path := range slices.Sorted(maps.Keys(found)) {
+		entries, fileProblems := lintFile(path)
+
+		problems = append(problems, fileProblems...)
+
+		for _, e := range entries {
+			if e.trivia.Media != "" {
+				_, err := resolveMedia(paths, path, e.trivia.Media)
+				if err != nil {
+					problems = append(problems, Problem{path, e.line, err.Error()})
+				}
+			}
+
+			id := e.trivia.getId()
+
+			if first, exists := seen[id]; exists {
+				problems = append(problems, Problem{path, e.line, fmt.Sprintf("duplicate of %s", first)})
+			} else {
+				seen[id] = fmt.Sprintf("%s:%d", path, e.line)
+			}
+
+			if colors != nil {
+				if _, exists := lookupColor(colors, e.trivia.Category); !exists {
+					problems = append(problems, Problem{path, e.line, fmt.Sprintf("category `%s` missing from colors file", e.trivia.Category)})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		return cmp.Or(cmp.Compare(a.path, b.path), cmp.Compare(a.line, b.line))
+	})
+
+	return problems, nil
+}
+
+func newLintCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint <path>...",
+		Short: "Checks question files for problems, exiting non-zero if any are found.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			problems, err := lint(args)
+			if err != nil {
+				return err
+			}
+
+			for _, p := range problems {
+				fmt.Fprintln(cmd.OutOrStdout(), p)
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("found %d problems", len(problems))
+			}
+
+			return nil
+		},
+	}
+}
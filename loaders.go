@@ -0,0 +1,325 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// An entry is a single question parsed from a file,
+// along with the line on which it was found
+type entry struct {
+	line   int
+	trivia *Trivia
+}
+
+// A loader parses the contents of a question file, calling skip
+// for every entry that cannot be parsed
+type loader func(r io.Reader, skip func(line int, reason string)) ([]entry, error)
+
+var loaders = map[string]loader{
+	".trivia": loadTrivia,
+	".json":   loadJson,
+	".yaml":   loadYaml,
+	".yml":    loadYaml,
+	".csv":    loadCsv,
+}
+
+// getLoader returns the loader registered for the extension of the given path,
+// falling back to the native format for unrecognized extensions
+func getLoader(path string) loader {
+	l, exists := loaders[strings.ToLower(filepath.Ext(path))]
+	if !exists {
+		return loadTrivia
+	}
+
+	return l
+}
+
+func matchesExtension(path string) bool {
+	return len(extensions) == 0 || slices.Contains(extensions, "") || slices.Contains(extensions, filepath.Ext(path))
+}
+
+// A record is the representation of a question shared by the structured file formats
+type record struct {
+	Question     string   `json:"question" yaml:"question"`
+	Answer       string   `json:"answer" yaml:"answer"`
+	Alternatives []string `json:"alternatives,omitempty" yaml:"alternatives,omitempty"`
+	Category     string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Incorrect    []string `json:"incorrect,omitempty" yaml:"incorrect,omitempty"`
+	Difficulty   string   `json:"difficulty,omitempty" yaml:"difficulty,omitempty"`
+	Media        string   `json:"media,omitempty" yaml:"media,omitempty"`
+	Explanation  string   `json:"explanation,omitempty" yaml:"explanation,omitempty"`
+	Source       string   `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+func (r *record) toTrivia() *Trivia {
+	return &Trivia{
+		Question:     r.Question,
+		Answer:       r.Answer,
+		Alternatives: r.Alternatives,
+		Category:     Category(r.Category),
+		Incorrect:    r.Incorrect,
+		Difficulty:   Difficulty(r.Difficulty),
+		Media:        r.Media,
+		Explanation:  r.Explanation,
+		Source:       r.Source,
+	}
+}
+
+func loadTrivia(r io.Reader, skip func(line int, reason string)) ([]entry, error) {
+	entries := []entry{}
+
+	s := bufio.NewScanner(r)
+	b := make([]byte, 0, 64*1024)
+	s.Buffer(b, 1024*1024)
+	s.Split(bufio.ScanLines)
+
+	l := 0
+
+	for s.Scan() {
+		l += 1
+
+		line := s.Text()
+
+		if line == "" {
+			continue
+		}
+
+		split := strings.Split(line, "|")
+
+		if len(split) < 2 {
+			skip(l, "missing answer")
+
+			continue
+		}
+
+		t := &Trivia{
+			Question: split[0],
+			Answer:   split[1],
+		}
+
+		if len(split) > 2 {
+			t.Category = Category(split[2])
+		}
+
+		err := parseFields(t, split[min(len(split), 3):])
+		if err != nil {
+			skip(l, err.Error())
+
+			continue
+		}
+
+		entries = append(entries, entry{l, t})
+	}
+
+	return entries, s.Err()
+}
+
+// parseFields applies the optional key=value fields following
+// the category of an entry to the given question
+func parseFields(t *Trivia, fields []string) error {
+	for _, field := range fields {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("malformed field `%s`", field)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "incorrect":
+			t.Incorrect = splitList(value)
+		case "difficulty":
+			t.Difficulty = Difficulty(value)
+		case "media":
+			t.Media = strings.TrimSpace(value)
+		case "explanation":
+			t.Explanation = strings.TrimSpace(value)
+		case "source":
+			t.Source = strings.TrimSpace(value)
+		default:
+			return fmt.Errorf("unknown field `%s`", key)
+		}
+	}
+
+	return nil
+}
+
+func splitList(value string) []string {
+	list := []string{}
+
+	for item := range strings.SplitSeq(value, ";") {
+		item = strings.TrimSpace(item)
+
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// loadJson parses a top-level array of records
+func loadJson(r io.Reader, skip func(line int, reason string)) ([]entry, error) {
+	entries := []entry{}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("expected an array of questions")
+	}
+
+	for dec.More() {
+		offset := dec.InputOffset()
+		for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+			offset++
+		}
+
+		l := bytes.Count(data[:offset], []byte("\n")) + 1
+
+		var rec record
+
+		err = dec.Decode(&rec)
+
+		var syntaxError *json.SyntaxError
+
+		switch {
+		case errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF):
+			return entries, err
+		case err != nil:
+			skip(l, err.Error())
+
+			continue
+		}
+
+		entries = append(entries, entry{l, rec.toTrivia()})
+	}
+
+	return entries, nil
+}
+
+// loadYaml parses a top-level sequence of records
+func loadYaml(r io.Reader, skip func(line int, reason string)) ([]entry, error) {
+	entries := []entry{}
+
+	var doc yaml.Node
+
+	err := yaml.NewDecoder(r).Decode(&doc)
+	switch {
+	case errors.Is(err, io.EOF):
+		return entries, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if len(doc.Content) < 1 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, errors.New("expected a sequence of questions")
+	}
+
+	for _, node := range doc.Content[0].Content {
+		var rec record
+
+		err = node.Decode(&rec)
+		if err != nil {
+			skip(node.Line, err.Error())
+
+			continue
+		}
+
+		entries = append(entries, entry{node.Line, rec.toTrivia()})
+	}
+
+	return entries, nil
+}
+
+// loadCsv parses RFC 4180 CSV with a header row naming the question, answer, alternatives,
+// category, incorrect, difficulty, media, explanation and source columns, where lists of answers are separated by semicolons
+func loadCsv(r io.Reader, skip func(line int, reason string)) ([]entry, error) {
+	entries := []entry{}
+
+	c := csv.NewReader(r)
+	c.FieldsPerRecord = -1
+
+	header, err := c.Read()
+	switch {
+	case errors.Is(err, io.EOF):
+		return entries, nil
+	case err != nil:
+		return nil, err
+	}
+
+	columns := map[string]int{}
+
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"question", "answer"} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		i, exists := columns[name]
+		if !exists || i >= len(row) {
+			return ""
+		}
+
+		return row[i]
+	}
+
+	for {
+		row, err := c.Read()
+
+		var parseError *csv.ParseError
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return entries, nil
+		case errors.As(err, &parseError):
+			skip(parseError.StartLine, parseError.Err.Error())
+
+			continue
+		case err != nil:
+			return entries, err
+		}
+
+		l, _ := c.FieldPos(0)
+
+		entries = append(entries, entry{l, &Trivia{
+			Question:     field(row, "question"),
+			Answer:       field(row, "answer"),
+			Alternatives: splitList(field(row, "alternatives")),
+			Category:     Category(field(row, "category")),
+			Incorrect:    splitList(field(row, "incorrect")),
+			Difficulty:   Difficulty(field(row, "difficulty")),
+			Media:        strings.TrimSpace(field(row, "media")),
+			Explanation:  strings.TrimSpace(field(row, "explanation")),
+			Source:       strings.TrimSpace(field(row, "source")),
+		}})
+	}
+}
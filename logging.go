@@ -0,0 +1,92 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// StatusWriter records the status code written by a handler, so that it can be logged
+type StatusWriter struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (w *StatusWriter) WriteHeader(status int) {
+	w.status = status
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer, e.g. to flush event streams
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// newLogger builds a logger from the --log-level and --log-format flags,
+// with --verbose being equivalent to --log-level debug
+func newLogger() (*slog.Logger, error) {
+	var level slog.Level
+
+	err := level.UnmarshalText([]byte(logLevel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q", logLevel)
+	}
+
+	if verbose {
+		level = min(level, slog.LevelDebug)
+	}
+
+	options := &slog.HandlerOptions{
+		Level: level,
+	}
+
+	switch logFormat {
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stdout, options)), nil
+	case "text":
+		options.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, a.Value.Time().Format(logDate))
+			}
+
+			return a
+		}
+
+		return slog.New(slog.NewTextHandler(os.Stdout, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (must be text or json)", logFormat)
+	}
+}
+
+// logRequest serves a request, then logs it at debug level along with the route it matched
+func logRequest(route Route, w http.ResponseWriter, r *http.Request, serve func(http.ResponseWriter), metrics *Metrics) {
+	startTime := time.Now()
+
+	sw := &StatusWriter{ResponseWriter: w, status: http.StatusOK}
+
+	serve(sw)
+
+	duration := time.Since(startTime)
+
+	metrics.requestServed(route, duration)
+
+	if !slog.Default().Enabled(r.Context(), slog.LevelDebug) {
+		return
+	}
+
+	slog.Debug("Served request",
+		"method", route.method,
+		"route", route.path,
+		"uri", r.RequestURI,
+		"remote", realIP(r),
+		"status", sw.status,
+		"duration", duration)
+}
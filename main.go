@@ -7,7 +7,9 @@ package main
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -21,22 +23,38 @@ const (
 )
 
 var (
-	bind           string
-	colorsFile     string
-	exitOnError    bool
-	export         bool
-	extension      string
-	html           bool
-	port           uint16
-	profile        bool
-	recursive      bool
-	reload         bool
-	reloadInterval string
-	settings       bool
-	tlsCert        string
-	tlsKey         string
-	verbose        bool
-	version        bool
+	adminCa         string
+	adminHtpasswd   string
+	adminTokens     []string
+	api             bool
+	bind            string
+	checkAnswers    bool
+	colorsFile      string
+	exitOnError     bool
+	export          bool
+	extensions      []string
+	html            bool
+	htmlAllow       []string
+	logFormat       string
+	logLevel        string
+	markdown        bool
+	metricsEnabled  bool
+	noRepeat        bool
+	port            uint16
+	profile         bool
+	recursive       bool
+	reload          bool
+	reloadInterval  string
+	roomsEnabled    bool
+	searchEnabled   bool
+	selection       string
+	settings        bool
+	shutdownTimeout string
+	tlsCert         string
+	tlsKey          string
+	verbose         bool
+	version         bool
+	watch           bool
 )
 
 func main() {
@@ -51,6 +69,25 @@ func main() {
 				return errors.New("TLS certificate and keyfile must both be specified to enable HTTPS")
 			}
 
+			if adminCa != "" && tlsCert == "" {
+				return errors.New("client certificate authentication requires HTTPS to be enabled")
+			}
+
+			if html && markdown {
+				return errors.New("--html and --markdown cannot be used together")
+			}
+
+			var err error
+
+			allowlist, err = parseAllowlist(htmlAllow)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(selectionModes, selection) {
+				return fmt.Errorf("invalid selection mode %q (must be question, category, or weighted)", selection)
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -58,22 +95,40 @@ func main() {
 		},
 	}
 
+	cmd.Flags().StringVar(&adminCa, "admin-ca", "", "require client certificates signed by this CA for admin routes (requires --tls-cert)")
+	cmd.Flags().StringVar(&adminHtpasswd, "admin-htpasswd", "", "htpasswd file of users allowed to access admin routes")
+	cmd.Flags().StringSliceVar(&adminTokens, "admin-token", []string{}, "bearer token allowed to access admin routes (can be specified multiple times)")
+	cmd.Flags().BoolVar(&api, "api", false, "enable JSON API at /api/v1")
 	cmd.Flags().StringVarP(&bind, "bind", "b", "0.0.0.0", "address to bind to")
-	cmd.Flags().StringVarP(&colorsFile, "colors", "c", "", "file from which to load color schemes")
+	cmd.Flags().BoolVar(&checkAnswers, "check-answers", false, "have players submit answers to be checked by the server")
+	cmd.PersistentFlags().StringVarP(&colorsFile, "colors", "c", "", "file from which to load color schemes")
 	cmd.Flags().BoolVar(&exitOnError, "exit-on-error", false, "shut down webserver on error, instead of just printing the error")
 	cmd.Flags().BoolVar(&export, "export", false, "allow exporting of trivia database")
-	cmd.Flags().StringVar(&extension, "extension", ".trivia", "only process files ending in this extension (leave empty to match all files)")
-	cmd.Flags().BoolVar(&html, "html", false, "allow arbitrary html tags in input")
+	cmd.PersistentFlags().StringSliceVar(&extensions, "extension", []string{".trivia"}, "only process files ending in these extensions (leave empty to match all files)")
+	cmd.PersistentFlags().BoolVar(&html, "html", false, "allow html tags from --html-allow in input")
+	cmd.PersistentFlags().StringSliceVar(&htmlAllow, "html-allow", []string{"u", "em", "strong", "sup", "sub", "br", "code", "ruby", "rt", "rp"}, "html tags (and attributes, e.g. a:href) allowed by --html")
+	cmd.Flags().StringVar(&logFormat, "log-format", "text", "format of log output (text or json)")
+	cmd.Flags().StringVar(&logLevel, "log-level", "info", "minimum level of log output (debug, info, warn, or error)")
+	cmd.PersistentFlags().BoolVar(&markdown, "markdown", false, "render a safe subset of markdown in input")
+	cmd.Flags().BoolVar(&metricsEnabled, "metrics", false, "enable Prometheus metrics at /metrics")
+	cmd.Flags().BoolVar(&noRepeat, "no-repeat", false, "deal every question once per session before repeating any")
 	cmd.Flags().Uint16VarP(&port, "port", "p", 8080, "port to listen on")
 	cmd.Flags().BoolVar(&profile, "profile", false, "register net/http/pprof handlers")
 	cmd.Flags().BoolVar(&reload, "reload", false, "allow live-reload of questions")
 	cmd.Flags().StringVar(&reloadInterval, "reload-interval", "", "interval at which to rebuild question list (e.g. \"5m\" or \"1h\")")
-	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "recurse into directories")
+	cmd.Flags().BoolVar(&roomsEnabled, "rooms", false, "enable live multiplayer rooms at /rooms")
+	cmd.PersistentFlags().BoolVarP(&recursive, "recursive", "r", false, "recurse into directories")
+	cmd.Flags().BoolVar(&searchEnabled, "search", false, "enable question search at /search")
+	cmd.Flags().StringVar(&selection, "selection", SelectQuestion, "how to pick random questions (question, category, or weighted)")
 	cmd.Flags().BoolVar(&settings, "settings", true, "enable settings page at /settings")
+	cmd.Flags().StringVar(&shutdownTimeout, "shutdown-timeout", "10s", "time to wait for in-flight requests to finish when shutting down")
 	cmd.Flags().StringVar(&tlsCert, "tls-cert", "", "path to TLS certificate")
 	cmd.Flags().StringVar(&tlsKey, "tls-key", "", "path to TLS keyfile")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "log requests to stdout")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "log requests to stdout (equivalent to --log-level debug)")
 	cmd.Flags().BoolVarP(&version, "version", "V", false, "display version and exit")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "rebuild question list when files under the provided paths change")
+
+	cmd.AddCommand(newLintCommand())
 
 	cmd.Flags().SetInterspersed(true)
 
@@ -89,7 +144,9 @@ func main() {
 
 	err := cmd.Execute()
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("Exiting", "error", err)
+
+		os.Exit(1)
 	}
 }
 
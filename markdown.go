@@ -0,0 +1,386 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	stdhtml "html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An inline is a piece of rendered Markdown, which is either HTML or a run of emphasis delimiters
+type inline struct {
+	html string
+
+	// Delimiter is the character of an emphasis run, or zero for HTML
+	delimiter byte
+	length    int
+	remaining int
+	canOpen   bool
+	canClose  bool
+
+	// Opens and closes are the tags written after and before the unused delimiters, respectively
+	opens  []string
+	closes []string
+}
+
+func isMarkdownPunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// isFlanking reports whether a delimiter run is left- and right-flanking, given the characters
+// on either side of it, where a space stands in for the start or end of the text
+func isFlanking(before, after rune) (bool, bool) {
+	left := !unicode.IsSpace(after) &&
+		(!isMarkdownPunctuation(after) || unicode.IsSpace(before) || isMarkdownPunctuation(before))
+
+	right := !unicode.IsSpace(before) &&
+		(!isMarkdownPunctuation(before) || unicode.IsSpace(after) || isMarkdownPunctuation(after))
+
+	return left, right
+}
+
+// renderMarkdown renders a safe subset of CommonMark inline syntax as HTML: emphasis, code spans,
+// links, autolinks, backslash escapes, and hard line breaks. Everything else is escaped, and
+// links whose destinations are not relative or use unsafe schemes are rendered as plain text.
+func renderMarkdown(text string) string {
+	return renderInlines(text, true)
+}
+
+func renderInlines(text string, links bool) string {
+	inlines := []*inline{}
+
+	var b strings.Builder
+
+	flush := func() {
+		if b.Len() > 0 {
+			inlines = append(inlines, &inline{html: b.String()})
+
+			b.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			b.WriteString("<br>\n")
+
+			i += 2
+		case c == '\\' && i+1 < len(text) && text[i+1] < utf8.RuneSelf && isMarkdownPunctuation(rune(text[i+1])):
+			b.WriteString(stdhtml.EscapeString(text[i+1 : i+2]))
+
+			i += 2
+		case c == '\n':
+			// Two or more trailing spaces make a hard line break, and are otherwise dropped
+			trimmed := strings.TrimRight(b.String(), " ")
+			hard := len(b.String())-len(trimmed) >= 2
+
+			b.Reset()
+			b.WriteString(trimmed)
+
+			if hard {
+				b.WriteString("<br>")
+			}
+
+			b.WriteString("\n")
+
+			i++
+		case c == '`':
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+
+			end := findBacktickRun(text, i+n, n)
+			if end < 0 {
+				b.WriteString(text[i : i+n])
+
+				i += n
+
+				continue
+			}
+
+			code := strings.ReplaceAll(text[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+
+			b.WriteString("<code>" + stdhtml.EscapeString(code) + "</code>")
+
+			i = end + n
+		case c == '<':
+			end := strings.IndexAny(text[i+1:], "<> \t\n")
+			if end >= 0 && text[i+1+end] == '>' {
+				url := text[i+1 : i+1+end]
+
+				scheme, _, found := strings.Cut(url, ":")
+				if links && found && len(scheme) > 1 && isSafeUrl(url) {
+					b.WriteString("<a href=\"" + stdhtml.EscapeString(url) + "\">" + stdhtml.EscapeString(url) + "</a>")
+
+					i += end + 2
+
+					continue
+				}
+			}
+
+			b.WriteString("&lt;")
+
+			i++
+		case c == '[' && links:
+			label, destination, title, length := parseLink(text[i:])
+			if length < 1 {
+				b.WriteString("[")
+
+				i++
+
+				continue
+			}
+
+			if isSafeUrl(destination) {
+				b.WriteString("<a href=\"" + stdhtml.EscapeString(destination) + "\"")
+
+				if title != "" {
+					b.WriteString(" title=\"" + stdhtml.EscapeString(title) + "\"")
+				}
+
+				b.WriteString(">" + renderInlines(label, false) + "</a>")
+			} else {
+				b.WriteString(renderInlines(label, false))
+			}
+
+			i += length
+		case c == '*' || c == '_':
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], string(c)))
+
+			before, after := ' ', ' '
+
+			if i > 0 {
+				before, _ = utf8.DecodeLastRuneInString(text[:i])
+			}
+
+			if i+n < len(text) {
+				after, _ = utf8.DecodeRuneInString(text[i+n:])
+			}
+
+			left, right := isFlanking(before, after)
+
+			d := &inline{
+				delimiter: c,
+				length:    n,
+				remaining: n,
+				canOpen:   left,
+				canClose:  right,
+			}
+
+			// Underscores cannot open or close emphasis within a word
+			if c == '_' {
+				d.canOpen = left && (!right || isMarkdownPunctuation(before))
+				d.canClose = right && (!left || isMarkdownPunctuation(after))
+			}
+
+			flush()
+
+			inlines = append(inlines, d)
+
+			i += n
+		default:
+			r, size := utf8.DecodeRuneInString(text[i:])
+
+			b.WriteString(stdhtml.EscapeString(string(r)))
+
+			i += size
+		}
+	}
+
+	flush()
+
+	matchEmphasis(inlines)
+
+	b.Reset()
+
+	for _, in := range inlines {
+		if in.delimiter == 0 {
+			b.WriteString(in.html)
+
+			continue
+		}
+
+		for _, tag := range in.closes {
+			b.WriteString(tag)
+		}
+
+		b.WriteString(strings.Repeat(string(in.delimiter), in.remaining))
+
+		for _, tag := range in.opens {
+			b.WriteString(tag)
+		}
+	}
+
+	return b.String()
+}
+
+// matchEmphasis pairs up emphasis delimiter runs, following the CommonMark algorithm
+func matchEmphasis(inlines []*inline) {
+	for i, closer := range inlines {
+		if closer.delimiter == 0 || !closer.canClose {
+			continue
+		}
+
+		for closer.remaining > 0 {
+			opener := -1
+
+			for j := i - 1; j >= 0; j-- {
+				o := inlines[j]
+
+				if o.delimiter != closer.delimiter || !o.canOpen || o.remaining < 1 {
+					continue
+				}
+
+				// The sum of the lengths of runs which can both open and close must not be a multiple of 3
+				if (o.canClose || closer.canOpen) && (o.length+closer.length)%3 == 0 && (o.length%3 != 0 || closer.length%3 != 0) {
+					continue
+				}
+
+				opener = j
+
+				break
+			}
+
+			if opener < 0 {
+				break
+			}
+
+			o := inlines[opener]
+
+			tag := "em"
+			use := 1
+
+			if o.remaining >= 2 && closer.remaining >= 2 {
+				tag = "strong"
+				use = 2
+			}
+
+			o.remaining -= use
+			closer.remaining -= use
+
+			o.opens = slices.Insert(o.opens, 0, "<"+tag+">")
+			closer.closes = append(closer.closes, "</"+tag+">")
+
+			// Delimiters between a matched pair can no longer be matched
+			for _, between := range inlines[opener+1 : i] {
+				between.canOpen = false
+				between.canClose = false
+			}
+		}
+	}
+}
+
+// findBacktickRun returns the index of the next run of exactly n backticks at or after start, or -1
+func findBacktickRun(text string, start, n int) int {
+	for i := start; i < len(text); {
+		if text[i] != '`' {
+			i++
+
+			continue
+		}
+
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		if run == n {
+			return i
+		}
+
+		i += run
+	}
+
+	return -1
+}
+
+// parseLink parses an inline link of the form [label](destination "title") at the start
+// of the text, returning its parts along with its length, which is zero if there is none
+func parseLink(text string) (string, string, string, int) {
+	depth := 0
+	end := -1
+
+	for i := 0; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+
+	if end < 0 || end+1 >= len(text) || text[end+1] != '(' {
+		return "", "", "", 0
+	}
+
+	label := text[1:end]
+
+	// Destinations may contain balanced parentheses
+	closing := -1
+
+	for i, nested := end+2, 0; i < len(text) && closing < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '(':
+			nested++
+		case ')':
+			if nested == 0 {
+				closing = i - (end + 2)
+			}
+
+			nested--
+		}
+	}
+
+	if closing < 0 {
+		return "", "", "", 0
+	}
+
+	inside := strings.TrimSpace(text[end+2 : end+2+closing])
+
+	destination, title, _ := strings.Cut(inside, " ")
+	title = strings.TrimSpace(title)
+
+	if title != "" {
+		if len(title) < 2 || title[0] != '"' || title[len(title)-1] != '"' {
+			return "", "", "", 0
+		}
+
+		title = title[1 : len(title)-1]
+	}
+
+	return label, destination, title, end + 3 + closing
+}
+
+// renderTrivia renders every field of a question which is displayed as HTML,
+// keeping the fields as written so that they can be exported
+func renderTrivia(t *Trivia) {
+	original := *t
+
+	t.original = &original
+
+	t.Question = renderMarkdown(t.Question)
+	t.Answer = renderMarkdown(t.Answer)
+	t.Explanation = renderMarkdown(t.Explanation)
+
+	t.Alternatives = slices.Clone(t.Alternatives)
+	for i := range t.Alternatives {
+		t.Alternatives[i] = renderMarkdown(t.Alternatives[i])
+	}
+
+	t.Incorrect = slices.Clone(t.Incorrect)
+	for i := range t.Incorrect {
+		t.Incorrect[i] = renderMarkdown(t.Incorrect[i])
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain text", "Plain text", "Plain text"},
+		{"html is escaped", "<b>bold</b> & <script>", "&lt;b&gt;bold&lt;/b&gt; &amp; &lt;script&gt;"},
+		{"emphasis", "*em* and _em_", "<em>em</em> and <em>em</em>"},
+		{"strong", "**strong** and __strong__", "<strong>strong</strong> and <strong>strong</strong>"},
+		{"strong within emphasis", "*a **b** c*", "<em>a <strong>b</strong> c</em>"},
+		{"emphasis within strong", "**a *b* c**", "<strong>a <em>b</em> c</strong>"},
+		{"triple delimiters", "***both***", "<em><strong>both</strong></em>"},
+		{"rule of 3", "*foo**bar**baz*", "<em>foo<strong>bar</strong>baz</em>"},
+		{"rule of 3 mismatch", "*foo**bar*", "<em>foo**bar</em>"},
+		{"intraword underscores", "snake_case_name", "snake_case_name"},
+		{"intraword asterisks", "un*frigging*believable", "un<em>frigging</em>believable"},
+		{"unmatched delimiter", "2 * 3 = 6", "2 * 3 = 6"},
+		{"unclosed emphasis", "*open", "*open"},
+		{"code span", "`a < b`", "<code>a &lt; b</code>"},
+		{"code span with backticks", "`` a ` b ``", "<code>a ` b</code>"},
+		{"emphasis in code span", "`*not em*`", "<code>*not em*</code>"},
+		{"unclosed code span", "`open", "`open"},
+		{"escaped emphasis", "\\*not em\\*", "*not em*"},
+		{"escaped bracket", "\\[not a link](x)", "[not a link](x)"},
+		{"escaped html", "\\<b>", "&lt;b&gt;"},
+		{"backslash before letter", "C:\\path", "C:\\path"},
+		{"hard break with spaces", "line one  \nline two", "line one<br>\nline two"},
+		{"hard break with backslash", "line one\\\nline two", "line one<br>\nline two"},
+		{"soft break", "line one \nline two", "line one\nline two"},
+		{"link", "[site](https://example.com)", "<a href=\"https://example.com\">site</a>"},
+		{"link with title", "[site](https://example.com \"Title\")", "<a href=\"https://example.com\" title=\"Title\">site</a>"},
+		{"link with parentheses", "[wiki](https://en.wikipedia.org/wiki/Go_(game))", "<a href=\"https://en.wikipedia.org/wiki/Go_(game)\">wiki</a>"},
+		{"relative link", "[page](/about)", "<a href=\"/about\">page</a>"},
+		{"emphasis in link", "[*em*](/x)", "<a href=\"/x\"><em>em</em></a>"},
+		{"link in link label", "[[inner](/a)](/b)", "<a href=\"/b\">[inner](/a)</a>"},
+		{"javascript link", "[click](javascript:alert(1))", "click"},
+		{"uppercase javascript link", "[click](JavaScript:alert(1))", "click"},
+		{"data link", "[click](data:text/html,<script>alert(1)</script>)", "click"},
+		{"quote in link", "[x](/a\"onmouseover=\"alert(1))", "<a href=\"/a&#34;onmouseover=&#34;alert(1)\">x</a>"},
+		{"autolink", "<https://example.com>", "<a href=\"https://example.com\">https://example.com</a>"},
+		{"mailto autolink", "<mailto:a@example.com>", "<a href=\"mailto:a@example.com\">mailto:a@example.com</a>"},
+		{"javascript autolink", "<javascript:alert(1)>", "&lt;javascript:alert(1)&gt;"},
+		{"not an autolink", "<not a link>", "&lt;not a link&gt;"},
+		{"unclosed link", "[label](https://example.com", "[label](https://example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := renderMarkdown(test.text); got != test.want {
+				t.Errorf("renderMarkdown(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// mediaTypes is a mapping of the extensions of files which can be attached to questions to their content types
+var mediaTypes = map[string]string{
+	".avif": "image/avif",
+	".gif":  "image/gif",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".wav":  "audio/wav",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+}
+
+// A Media is an image, audio, or video file attached to a question
+type Media struct {
+	Url  string `json:"url"`
+	Type string `json:"type"`
+}
+
+// Kind returns the top-level content type of the media, i.e. image, audio, or video
+func (m *Media) Kind() string {
+	kind, _, _ := strings.Cut(m.Type, "/")
+
+	return kind
+}
+
+// within returns the path of target relative to root, and whether target is located under root
+func within(root, target string) (string, bool) {
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return rel, true
+}
+
+// mediaRoot returns the directory under which media for a question path may be located,
+// which is the directory containing it if the path is a single file
+func mediaRoot(path string) string {
+	info, err := os.Stat(path)
+	if err == nil && !info.IsDir() {
+		return filepath.Dir(path)
+	}
+
+	return path
+}
+
+// resolveMedia locates the media referenced by a question, relative to the file containing it,
+// which must be located under one of the provided paths so that it can be served via /media
+func resolveMedia(paths []string, file, media string) (*Media, error) {
+	if filepath.IsAbs(media) {
+		return nil, fmt.Errorf("media `%s` must be relative to the question file", media)
+	}
+
+	mediaType, exists := mediaTypes[strings.ToLower(filepath.Ext(media))]
+	if !exists {
+		return nil, fmt.Errorf("unsupported media type `%s`", filepath.Ext(media))
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Join(filepath.Dir(file), filepath.FromSlash(media)))
+	if err != nil {
+		return nil, fmt.Errorf("media `%s` not found", media)
+	}
+
+	for i, path := range paths {
+		rel, found := within(mediaRoot(path), resolved)
+		if !found {
+			continue
+		}
+
+		u := url.URL{Path: fmt.Sprintf("/media/%d/%s", i, filepath.ToSlash(rel))}
+
+		return &Media{
+			Url:  u.EscapedPath(),
+			Type: mediaType,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("media `%s` is outside of the provided paths", media)
+}
+
+// attachMedia resolves the media referenced by the entries of a question file,
+// logging a warning for and ignoring any which cannot be served
+func attachMedia(paths []string, path string, entries []entry) {
+	for _, e := range entries {
+		if e.trivia.Media == "" {
+			continue
+		}
+
+		media, err := resolveMedia(paths, path, e.trivia.Media)
+		if err != nil {
+			slog.Warn("Ignored media",
+				"file", path,
+				"line", e.line,
+				"reason", err)
+
+			continue
+		}
+
+		e.trivia.attachment = media
+	}
+}
+
+// trackMedia adjusts the number of served questions referencing the media attached
+// to a question, and must be called with the write lock held
+func (q *Questions) trackMedia(t *Trivia, delta int) {
+	if t.attachment == nil {
+		return
+	}
+
+	q.media[t.attachment.Url] += delta
+
+	if q.media[t.attachment.Url] < 1 {
+		delete(q.media, t.attachment.Url)
+	}
+}
+
+// hasMedia reports whether any served question references the media at the given URL
+func (q *Questions) hasMedia(url string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	_, exists := q.media[url]
+
+	return exists
+}
+
+// serveMedia serves files attached to questions from under the provided paths,
+// which are identified by their index in the list of paths
+func serveMedia(paths []string, questions *Questions, errorChannel chan<- error) httprouter.Handle {
+	roots := make([]string, len(paths))
+
+	for i := range paths {
+		roots[i] = mediaRoot(paths[i])
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		i, err := strconv.Atoi(p.ByName("root"))
+		if err != nil || i < 0 || i >= len(roots) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		u := url.URL{Path: fmt.Sprintf("/media/%d/%s", i, strings.TrimPrefix(p.ByName("path"), "/"))}
+
+		mediaType, exists := mediaTypes[strings.ToLower(filepath.Ext(p.ByName("path")))]
+		if !exists || !questions.hasMedia(u.EscapedPath()) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		resolved, err := filepath.EvalSymlinks(filepath.Join(roots[i], filepath.FromSlash(p.ByName("path"))))
+		if err != nil {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if _, found := within(roots[i], resolved); !found {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		f, err := os.Open(resolved)
+		if err != nil {
+			http.NotFound(w, r)
+
+			return
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		switch {
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			errorChannel <- err
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		case err != nil || info.IsDir():
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaType)
+
+		// Images such as SVGs are sandboxed, in case they are opened directly
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+
+		securityHeaders(w)
+
+		http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	}
+}
+
+func registerMedia(mux *Router, paths []string, questions *Questions, errorChannel chan<- error) {
+	mux.GET("/media/:root/*path", serveMedia(paths, questions, errorChannel))
+}
@@ -0,0 +1,265 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Buckets are the upper bounds, in seconds, of the latency histograms
+var buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type Histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram() *Histogram {
+	return &Histogram{
+		counts: make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+
+	for i := range buckets {
+		if seconds <= buckets[i] {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+// write appends the histogram in Prometheus text exposition format,
+// with the given labels preceding the bucket label
+func (h *Histogram) write(b *bytes.Buffer, name, labels string) {
+	separator := ""
+	if labels != "" {
+		separator = ","
+	}
+
+	for i := range buckets {
+		fmt.Fprintf(b, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, separator, strconv.FormatFloat(buckets[i], 'g', -1, 64), h.counts[i])
+	}
+
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, separator, h.count)
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
+
+type Route struct {
+	method string
+	path   string
+}
+
+// Metrics collects counters and histograms for the /metrics endpoint.
+// All methods are safe to call on a nil *Metrics, in which case they do nothing.
+type Metrics struct {
+	mu sync.Mutex
+
+	// Served is a mapping of categories to the number of questions served from them
+	served map[Category]uint64
+
+	// NotFound is a mapping of the kind of lookup (question or route) to failed lookups
+	notFound map[string]uint64
+
+	errors uint64
+
+	reloads        uint64
+	reloadFailures uint64
+	reloadDuration *Histogram
+
+	requests map[Route]*Histogram
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		served:         map[Category]uint64{},
+		notFound:       map[string]uint64{},
+		reloadDuration: newHistogram(),
+		requests:       map[Route]*Histogram{},
+	}
+}
+
+func (m *Metrics) questionServed(category Category) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.served[category]++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) questionNotFound() {
+	m.lookupFailed("question")
+}
+
+func (m *Metrics) routeNotFound() {
+	m.lookupFailed("route")
+}
+
+func (m *Metrics) lookupFailed(kind string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.notFound[kind]++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) error() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.errors++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) reloaded(d time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reloads++
+
+	if failed {
+		m.reloadFailures++
+	}
+
+	m.reloadDuration.observe(d)
+}
+
+func (m *Metrics) requestServed(route Route, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, exists := m.requests[route]
+	if !exists {
+		h = newHistogram()
+
+		m.requests[route] = h
+	}
+
+	h.observe(d)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func writeHeader(b *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// write renders every metric in Prometheus text exposition format
+func (m *Metrics) write(b *bytes.Buffer, questions *Questions) {
+	questions.mu.RLock()
+	triviaCount := len(questions.list)
+	categoryCount := len(questions.index)
+	questions.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeHeader(b, "trivia_questions_loaded", "gauge", "Number of questions currently loaded.")
+	fmt.Fprintf(b, "trivia_questions_loaded %d\n", triviaCount)
+
+	writeHeader(b, "trivia_categories_loaded", "gauge", "Number of categories currently loaded.")
+	fmt.Fprintf(b, "trivia_categories_loaded %d\n", categoryCount)
+
+	writeHeader(b, "trivia_questions_served_total", "counter", "Number of questions served, by category.")
+	categories := make([]Category, 0, len(m.served))
+	for category := range m.served {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+
+	for _, category := range categories {
+		fmt.Fprintf(b, "trivia_questions_served_total{category=\"%s\"} %d\n", escapeLabel(category.String()), m.served[category])
+	}
+
+	writeHeader(b, "trivia_not_found_total", "counter", "Number of requests for unknown questions or routes.")
+	for _, kind := range []string{"question", "route"} {
+		fmt.Fprintf(b, "trivia_not_found_total{kind=\"%s\"} %d\n", kind, m.notFound[kind])
+	}
+
+	writeHeader(b, "trivia_errors_total", "counter", "Number of errors encountered.")
+	fmt.Fprintf(b, "trivia_errors_total %d\n", m.errors)
+
+	writeHeader(b, "trivia_reloads_total", "counter", "Number of times the question list has been rebuilt.")
+	fmt.Fprintf(b, "trivia_reloads_total %d\n", m.reloads)
+
+	writeHeader(b, "trivia_reload_failures_total", "counter", "Number of rebuilds which encountered errors.")
+	fmt.Fprintf(b, "trivia_reload_failures_total %d\n", m.reloadFailures)
+
+	writeHeader(b, "trivia_reload_duration_seconds", "histogram", "Time taken to rebuild the question list.")
+	m.reloadDuration.write(b, "trivia_reload_duration_seconds", "")
+
+	writeHeader(b, "trivia_request_duration_seconds", "histogram", "Time taken to serve requests, by route.")
+	routes := make([]Route, 0, len(m.requests))
+	for route := range m.requests {
+		routes = append(routes, route)
+	}
+	slices.SortFunc(routes, func(a, b Route) int {
+		return strings.Compare(a.path+" "+a.method, b.path+" "+b.method)
+	})
+
+	for _, route := range routes {
+		m.requests[route].write(b, "trivia_request_duration_seconds",
+			fmt.Sprintf("method=\"%s\",route=\"%s\"", route.method, escapeLabel(route.path)))
+	}
+}
+
+func serveMetrics(metrics *Metrics, questions *Questions, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		var b bytes.Buffer
+
+		metrics.write(&b, questions)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		securityHeaders(w)
+
+		_, err := w.Write(b.Bytes())
+		if err != nil {
+			errorChannel <- err
+
+			return
+		}
+	}
+}
+
+func registerMetrics(mux *Router, auth *Auth, metrics *Metrics, questions *Questions, errorChannel chan<- error) {
+	mux.GET("/metrics", auth.protect(serveMetrics(metrics, questions, errorChannel)))
+}
@@ -5,20 +5,19 @@ Copyright © 2026 Seednode <seednode@seedno.de>
 package main
 
 import (
+	"net/http"
 	"net/http/pprof"
-
-	"github.com/julienschmidt/httprouter"
 )
 
-func registerProfile(mux *httprouter.Router) {
-	mux.Handler("GET", "/pprof/allocs", pprof.Handler("allocs"))
-	mux.Handler("GET", "/pprof/block", pprof.Handler("block"))
-	mux.Handler("GET", "/pprof/goroutine", pprof.Handler("goroutine"))
-	mux.Handler("GET", "/pprof/heap", pprof.Handler("heap"))
-	mux.Handler("GET", "/pprof/mutex", pprof.Handler("mutex"))
-	mux.Handler("GET", "/pprof/threadcreate", pprof.Handler("threadcreate"))
-	mux.HandlerFunc("GET", "/pprof/cmdline", pprof.Cmdline)
-	mux.HandlerFunc("GET", "/pprof/profile", pprof.Profile)
-	mux.HandlerFunc("GET", "/pprof/symbol", pprof.Symbol)
-	mux.HandlerFunc("GET", "/pprof/trace", pprof.Trace)
+func registerProfile(mux *Router, auth *Auth) {
+	mux.Handler("GET", "/pprof/allocs", auth.protectHandler(pprof.Handler("allocs")))
+	mux.Handler("GET", "/pprof/block", auth.protectHandler(pprof.Handler("block")))
+	mux.Handler("GET", "/pprof/goroutine", auth.protectHandler(pprof.Handler("goroutine")))
+	mux.Handler("GET", "/pprof/heap", auth.protectHandler(pprof.Handler("heap")))
+	mux.Handler("GET", "/pprof/mutex", auth.protectHandler(pprof.Handler("mutex")))
+	mux.Handler("GET", "/pprof/threadcreate", auth.protectHandler(pprof.Handler("threadcreate")))
+	mux.Handler("GET", "/pprof/cmdline", auth.protectHandler(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handler("GET", "/pprof/profile", auth.protectHandler(http.HandlerFunc(pprof.Profile)))
+	mux.Handler("GET", "/pprof/symbol", auth.protectHandler(http.HandlerFunc(pprof.Symbol)))
+	mux.Handler("GET", "/pprof/trace", auth.protectHandler(http.HandlerFunc(pprof.Trace)))
 }
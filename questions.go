@@ -13,8 +13,11 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"log/slog"
+	"maps"
 	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -33,6 +36,9 @@ type Color struct {
 	Abbreviation string
 	Hex          string
 	Hash         string
+
+	// Weight is the relative likelihood of the category being picked in weighted selection mode
+	Weight float64
 }
 
 const (
@@ -42,14 +48,18 @@ const (
 
 var (
 	DefaultColor = Color{
-		Hex:  lightBlue,
-		Hash: getChecksum(lightBlue),
+		Hex:    lightBlue,
+		Hash:   getChecksum(lightBlue),
+		Weight: 1,
 	}
 
 	ErrorColor = Color{
-		Hex:  darkRed,
-		Hash: getChecksum(darkRed),
+		Hex:    darkRed,
+		Hash:   getChecksum(darkRed),
+		Weight: 1,
 	}
+
+	validColor = regexp.MustCompile(ValidHexColor)
 )
 
 type Question struct {
@@ -58,20 +68,74 @@ type Question struct {
 	Theme        string
 	Question     any
 	Answer       any
+	Explanation  any
+	Source       string
+	SourceLink   bool
 	Category     Category
 	Color        string
+	Media        *Media
+	Choices      []Choice
+	Check        bool
 	Settings     any
 }
 
+type Choice struct {
+	Text    any
+	Correct bool
+}
+
 type Trivia struct {
-	Question string
-	Answer   string
-	Category Category
+	Question     string
+	Answer       string
+	Alternatives []string
+	Category     Category
+	Incorrect    []string
+	Difficulty   Difficulty
+
+	// Media is the path of the file attached to the question, relative to the question file
+	Media string
+
+	// Attachment is the resolved media, if it could be located under the provided paths
+	attachment *Media
+
+	// Explanation is an optional elaboration on the answer, and
+	// Source an optional citation, which may be a URL
+	Explanation string
+	Source      string
+
+	// Original is the question as written, if its fields were rendered from Markdown
+	original *Trivia
+}
+
+// written returns the question as it was written in its file, before any rendering
+func (t *Trivia) written() *Trivia {
+	if t.original != nil {
+		return t.original
+	}
+
+	return t
+}
+
+// isSourceLink reports whether a source citation is a web address, which can be displayed as a link
+func isSourceLink(source string) bool {
+	u, err := url.Parse(source)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// displayedAnswer returns the answer followed by its alternatives, as they were written,
+// so that answers such as "Red; Green; Blue" are shown in full
+func (t *Trivia) displayedAnswer() string {
+	return strings.Join(append([]string{t.Answer}, t.Alternatives...), "; ")
 }
 
 func (t *Trivia) getId() QuestionId {
+	t = t.written()
+
+	answer := strings.Join(append([]string{t.Answer}, t.Alternatives...), ";")
+
 	sha1hash := sha1.New()
-	sha1hash.Write([]byte(t.Question + t.Answer + t.Category.String()))
+	sha1hash.Write([]byte(t.Question + answer + t.Category.String()))
 	sha1string := hex.EncodeToString(sha1hash.Sum(nil))
 
 	return QuestionId(uuid.NewSHA1(uuid.NameSpaceURL, []byte(sha1string)).String())
@@ -83,15 +147,162 @@ func (c Category) String() string {
 	return string(c)
 }
 
+// parseCategory normalizes a category path such as Science/Physics/Optics,
+// trimming whitespace from each of its segments and removing empty ones
+func parseCategory(path string) Category {
+	segments := []string{}
+
+	for segment := range strings.SplitSeq(path, "/") {
+		segment = strings.TrimSpace(segment)
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return Category(strings.Join(segments, "/"))
+}
+
+// parent returns the category containing this one, or an empty category if it is top-level
+func (c Category) parent() Category {
+	i := strings.LastIndexByte(string(c), '/')
+	if i < 0 {
+		return ""
+	}
+
+	return c[:i]
+}
+
+// name returns the last segment of the category path
+func (c Category) name() string {
+	return string(c[strings.LastIndexByte(string(c), '/')+1:])
+}
+
+// contains reports whether other is this category or one of its descendants
+func (c Category) contains(other Category) bool {
+	return c == other || strings.HasPrefix(string(other), string(c)+"/")
+}
+
+// A Difficulty is the optional rating of a question, where unrated questions have none
+type Difficulty string
+
+const (
+	Unrated Difficulty = ""
+	Easy    Difficulty = "easy"
+	Medium  Difficulty = "medium"
+	Hard    Difficulty = "hard"
+)
+
+// Difficulties is the list of selectable difficulties, in display order
+var Difficulties = [...]Difficulty{Easy, Medium, Hard, Unrated}
+
+func (d Difficulty) String() string {
+	if d == Unrated {
+		return "unrated"
+	}
+
+	return string(d)
+}
+
+// parseDifficulty returns the difficulty named by the given string,
+// treating an empty string as unrated
+func parseDifficulty(s string) (Difficulty, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if s == "" {
+		return Unrated, true
+	}
+
+	for _, d := range Difficulties {
+		if s == d.String() {
+			return d, true
+		}
+	}
+
+	return Unrated, false
+}
+
+// A DifficultySet is a bitmask of enabled difficulties, in the order of Difficulties,
+// where the empty set enables every difficulty
+type DifficultySet uint8
+
+// noDifficulties enables no difficulty, as its only bit belongs to none of them
+const noDifficulties DifficultySet = 1 << len(Difficulties)
+
+func (d Difficulty) bit() DifficultySet {
+	return 1 << slices.Index(Difficulties[:], d)
+}
+
+func (s DifficultySet) has(d Difficulty) bool {
+	return s == 0 || s&d.bit() != 0
+}
+
+func (s DifficultySet) String() string {
+	names := make([]string, 0, len(Difficulties))
+
+	for _, d := range Difficulties {
+		if s&d.bit() != 0 {
+			names = append(names, d.String())
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// parseDifficultySet returns the set of difficulties named in a comma-separated list,
+// ignoring any which are unknown, or noDifficulties if none of the names are known
+func parseDifficultySet(list string) DifficultySet {
+	var set DifficultySet
+
+	named := false
+
+	for name := range strings.SplitSeq(list, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		named = true
+
+		d, known := parseDifficulty(name)
+		if known {
+			set |= d.bit()
+		}
+	}
+
+	if named && set == 0 {
+		return noDifficulties
+	}
+
+	return set
+}
+
 type QuestionId string
 
+const NoQuestion QuestionId = "00000000-0000-0000-0000-000000000000"
+
+// maxExpansions is the number of expanded lists of categories cached before the cache is cleared
+const maxExpansions int = 1024
+
 func (q QuestionId) String() string {
 	return string(q)
 }
 
+// A File records the state of a question file as of the last load,
+// so that unchanged files can be skipped on reload
+type File struct {
+	modTime time.Time
+	size    int64
+
+	// Trivia is a mapping of the UUIDv5 identifiers of all questions
+	// loaded from this file to the first copy of each in the file
+	trivia map[QuestionId]*Trivia
+}
+
 type Questions struct {
 	mu sync.RWMutex
 
+	// Loading serializes calls to loadQuestions
+	loading sync.Mutex
+
 	// Index is a mapping of a string representing a trivia category
 	// to the UUIDv5 identifiers of all questions in that category
 	index map[Category][]QuestionId
@@ -99,6 +310,67 @@ type Questions struct {
 	// List is a mapping of a UUIDv5 string representing a trivia question
 	// to a pointer to the struct itself
 	list map[QuestionId]*Trivia
+
+	// Files is a mapping of the path of each loaded question file
+	// to the questions it contains
+	files map[string]*File
+
+	// Sources is a mapping of each question to the sorted paths of
+	// every loaded file containing it
+	sources map[QuestionId][]string
+
+	// Media is a count of the served questions referencing each media URL,
+	// so that only media attached to questions is served
+	media map[string]int
+
+	// Colors is a mapping of categories to their color schemes,
+	// loaded from the --colors file alongside the questions
+	colors map[Category]Color
+
+	// Terms is an inverted index mapping each normalized word of a question,
+	// answer, or category to the UUIDv5 identifiers of the questions containing it
+	terms map[string]map[QuestionId]bool
+
+	// Buckets is a mapping of categories to their questions grouped by difficulty,
+	// which is rebuilt on load so that random picks need not allocate
+	buckets map[Category]*Bucket
+
+	// Sorted is the sorted list of category names, which is replaced rather than
+	// modified on load, so that it can be shared with callers
+	sorted []string
+
+	// Expansions is a mapping of comma-separated lists of categories to the loaded categories
+	// they enable, which is cleared whenever the list of categories changes
+	expansions   map[string][]string
+	expansionsMu sync.Mutex
+}
+
+// Stats summarizes the result of a call to loadQuestions
+type Stats struct {
+	questions  int
+	categories int
+	colors     int
+
+	// Rejected is the list of lines of the colors file which could not be parsed
+	rejected []Problem
+
+	duration time.Duration
+}
+
+func newQuestions() *Questions {
+	return &Questions{
+		index:   map[Category][]QuestionId{},
+		list:    map[QuestionId]*Trivia{},
+		files:   map[string]*File{},
+		sources: map[QuestionId][]string{},
+		media:   map[string]int{},
+		colors:  map[Category]Color{},
+		terms:   map[string]map[QuestionId]bool{},
+		buckets: map[Category]*Bucket{},
+		sorted:  []string{},
+
+		expansions: map[string][]string{},
+	}
 }
 
 func (q *Questions) CategoryBytes() []byte {
@@ -113,37 +385,92 @@ func (q *Questions) CategoryBytes() []byte {
 	return list
 }
 
+// CategoryCount returns the number of loaded categories
+func (q *Questions) CategoryCount() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return len(q.index)
+}
+
+// CategoryStrings returns the sorted list of category names, which must not be modified
 func (q *Questions) CategoryStrings() []string {
-	var list []string
+	q.mu.RLock()
+	defer q.mu.RUnlock()
 
+	return q.sorted
+}
+
+// expand returns every loaded category which is one of, or a descendant of, the categories in
+// the comma-separated list. The result is cached, so that picks from the same enabled categories
+// need not allocate, and must not be modified.
+func (q *Questions) expand(list string) []string {
 	q.mu.RLock()
-	for category := range q.index {
-		list = append(list, category.String())
+	defer q.mu.RUnlock()
+
+	q.expansionsMu.Lock()
+	defer q.expansionsMu.Unlock()
+
+	expanded, exists := q.expansions[list]
+	if exists {
+		return expanded
 	}
-	q.mu.RUnlock()
 
-	slices.Sort(list)
+	categories := strings.Split(list, ",")
 
-	return list
-}
+	expanded = []string{}
 
-func (q *Questions) getRandomId(r *http.Request) QuestionId {
-	categories := getCategories(r, q)
+	for _, loaded := range q.sorted {
+		if slices.ContainsFunc(categories, func(c string) bool {
+			return Category(c).contains(Category(loaded))
+		}) {
+			expanded = append(expanded, loaded)
+		}
+	}
+
+	// Lists come from clients, so the cache is bounded
+	if len(q.expansions) >= maxExpansions {
+		clear(q.expansions)
+	}
 
+	q.expansions[list] = expanded
+
+	return expanded
+}
+
+// getEligible returns the identifiers of all questions in the provided categories and difficulties
+func (q *Questions) getEligible(categories []string, difficulties DifficultySet) []QuestionId {
 	ids := []QuestionId{}
 
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	for _, i := range categories {
-		ids = append(ids, q.index[Category(i)]...)
+	for _, c := range categories {
+		b, exists := q.buckets[Category(c)]
+		if !exists {
+			continue
+		}
+
+		for i, d := range Difficulties {
+			if difficulties.has(d) {
+				ids = append(ids, b.span(i)...)
+			}
+		}
 	}
 
-	if len(ids) < 1 {
-		return "00000000-0000-0000-0000-000000000000"
+	return ids
+}
+
+func (q *Questions) getRandomId(r *http.Request) QuestionId {
+	mode := getSelection(r)
+
+	var weights map[string]float64
+
+	if mode == SelectWeighted {
+		weights = getWeights(r)
 	}
 
-	return ids[rand.IntN(len(ids))]
+	return q.pick(getCategories(r, q), getDifficulties(r), mode, weights)
 }
 
 func (q *Questions) getTrivia(id QuestionId) *Trivia {
@@ -169,7 +496,12 @@ func getQuestionTemplate() string {
     <link rel="stylesheet" href="/css/{{.Theme}}.css" />
 	<link rel="stylesheet" href="/css/trivia.css" />
     <style>.footer {background-color:{{.Color}};}</style>
+    {{- if .Check}}
+    <script src="/js/checkAnswer.js" defer></script>
+    {{- else}}
     <script src="/js/toggleAnswer.js" defer></script>
+    {{- end}}
+    <script src="/js/multipleChoice.js" defer></script>
     <link rel="apple-touch-icon" sizes="180x180" href="/favicons/apple-touch-icon.webp" />
     <link rel="icon" type="image/webp" sizes="32x32" href="/favicons/favicon-32x32.webp" />
     <link rel="icon" type="image/webp" sizes="16x16" href="/favicons/favicon-16x16.webp" />
@@ -188,13 +520,77 @@ func getQuestionTemplate() string {
   {{.Settings}}
     <p id="hint">(Click on the question to load a new one)</p>
     <a href="/"><p id="question">{{.Question}}</p></a>
+    {{- with .Media}}
+    <div id="media">
+      {{- if eq .Kind "image"}}
+      <img src="{{.Url}}" alt="" />
+      {{- else if eq .Kind "audio"}}
+      <audio src="{{.Url}}" controls></audio>
+      {{- else}}
+      <video src="{{.Url}}" controls></video>
+      {{- end}}
+    </div>
+    {{- end}}
+    {{- if .Choices}}
+    <div id="choices">
+    {{- range .Choices}}
+      <button class="choice"{{if .Correct}} data-correct="true"{{end}}>{{.Text}}</button>
+    {{- end}}
+    </div>
+    {{- end}}
+    {{- if .Check}}
+    {{- if not .Choices}}
+    <form id="guess-form">
+      <input id="guess" type="text" autocomplete="off" placeholder="Your answer" />
+      <button id="submit-guess" type="submit">Submit</button>
+    </form>
+    {{- end}}
+    <p id="verdict"></p>
+    {{- end}}
     <button id="toggle-answer">Show Answer</button>
-    <div id="answer"><p>{{.Answer}}</p></div>
+    <div id="answer">
+      <p>{{.Answer}}</p>
+      <p id="explanation">{{.Explanation}}</p>
+      <p id="source">
+        {{- if .SourceLink}}Source: <a href="{{.Source}}">{{.Source}}</a>
+        {{- else if .Source}}Source: {{.Source}}
+        {{- end -}}
+      </p>
+    </div>
     <div class="footer"><p>{{.Category}} {{.Abbreviation}}</p></div>
   </body>
 </html>`
 }
 
+// getColor returns the color scheme for a category, taking the read lock
+func (q *Questions) getColor(category Category) Color {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return getColor(q.colors, category)
+}
+
+func getColor(colors map[Category]Color, category Category) Color {
+	c, exists := lookupColor(colors, category)
+	if !exists {
+		return DefaultColor
+	}
+
+	return c
+}
+
+// lookupColor returns the color of a category, inheriting it from the
+// nearest parent category if it does not define one of its own
+func lookupColor(colors map[Category]Color, category Category) (Color, bool) {
+	for ; category != ""; category = category.parent() {
+		if c, exists := colors[category]; exists {
+			return c, true
+		}
+	}
+
+	return Color{}, false
+}
+
 func getChecksum(hex string) string {
 	h := sha256.New()
 	h.Write(fmt.Appendf(nil, ".footer {background-color:%s;}", hex))
@@ -202,32 +598,32 @@ func getChecksum(hex string) string {
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
-func loadColors(path string, valid *regexp.Regexp, errorChannel chan<- error) map[Category]Color {
-	if colorsFile == "" {
-		return map[Category]Color{}
-	}
-
-	startTime := time.Now()
-
+// loadColors parses the --colors file, returning the valid mappings along with
+// each rejected line, or an error if the file could not be read
+func loadColors(path string) (map[Category]Color, []Problem, error) {
 	colors := map[Category]Color{}
+	rejected := []Problem{}
+
+	if path == "" {
+		return colors, rejected, nil
+	}
 
 	f, err := os.Open(path)
 	if err != nil {
-		errorChannel <- err
+		return nil, nil, err
 	}
-	defer func() {
-		err = f.Close()
-		if err != nil {
-			errorChannel <- err
-		}
-	}()
+	defer f.Close()
 
 	s := bufio.NewScanner(f)
 	b := make([]byte, 0, 64*1024)
 	s.Buffer(b, 1024*1024)
 	s.Split(bufio.ScanLines)
 
+	l := 0
+
 	for s.Scan() {
+		l += 1
+
 		line := s.Text()
 
 		if line == "" {
@@ -237,37 +633,46 @@ func loadColors(path string, valid *regexp.Regexp, errorChannel chan<- error) ma
 		split := strings.Split(line, "|")
 
 		var category Category
-		var abbreviation, hex string
+		var abbreviation, hex, reason string
+
+		weight := 1.0
 
 		switch len(split) {
 		case 2:
 			abbreviation = ""
-			category = Category(strings.TrimSpace(split[0]))
+			category = parseCategory(split[0])
 			hex = strings.TrimSpace(split[1])
 		case 3:
 			abbreviation = strings.TrimSpace(split[2])
-			category = Category(strings.TrimSpace(split[0]))
+			category = parseCategory(split[0])
+			hex = strings.TrimSpace(split[1])
+		case 4:
+			abbreviation = strings.TrimSpace(split[2])
+			category = parseCategory(split[0])
 			hex = strings.TrimSpace(split[1])
-		default:
-			if verbose {
-				errorChannel <- fmt.Errorf("invalid color mapping in `%s`", line)
-			}
 
-			continue
-		}
+			var valid bool
 
-		if category == "" {
-			if verbose {
-				errorChannel <- fmt.Errorf("no category name provided in `%s`", line)
+			weight, valid = parseWeight(split[3])
+			if !valid {
+				reason = "invalid weight"
 			}
+		default:
+			reason = "invalid mapping"
+		}
 
-			continue
+		switch {
+		case reason != "":
+		case category == "":
+			reason = "no category name"
+		case validColor.FindAllString(hex, -1) == nil:
+			reason = "invalid hex code"
 		}
 
-		if valid.FindAllString(hex, -1) == nil {
-			if verbose {
-				errorChannel <- fmt.Errorf("invalid color hex code in `%s`", line)
-			}
+		if reason != "" {
+			slog.Debug("Skipped color mapping", "file", path, "line", l, "reason", reason)
+
+			rejected = append(rejected, Problem{path, l, reason})
 
 			continue
 		}
@@ -276,17 +681,11 @@ func loadColors(path string, valid *regexp.Regexp, errorChannel chan<- error) ma
 			Abbreviation: abbreviation,
 			Hex:          hex,
 			Hash:         getChecksum(hex),
+			Weight:       weight,
 		}
 	}
 
-	if verbose {
-		fmt.Printf("%s | Loaded %d color mappings in %s\n",
-			startTime.Format(logDate),
-			len(colors),
-			time.Since(startTime))
-	}
-
-	return colors
+	return colors, rejected, s.Err()
 }
 
 func normalizePath(path string) (string, error) {
@@ -329,14 +728,38 @@ func validatePaths(args []string) ([]string, error) {
 	return paths, nil
 }
 
-func walkPath(path string, index map[Category][]QuestionId, list map[QuestionId]*Trivia, errorChannel chan<- error) ([]string, []string) {
-	triviaIndex, categoryIndex := []string{}, []string{}
+func getChoices(t *Trivia) []Choice {
+	if len(t.Incorrect) < 1 {
+		return nil
+	}
+
+	choices := make([]Choice, 0, len(t.Incorrect)+1)
+
+	choices = append(choices, Choice{Text: t.Answer, Correct: true})
+
+	for _, i := range t.Incorrect {
+		choices = append(choices, Choice{Text: i})
+	}
+
+	if html || markdown {
+		for i := range choices {
+			choices[i].Text = template.HTML(choices[i].Text.(string))
+		}
+	}
+
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
 
+	return choices
+}
+
+func walkPath(path string, found map[string]os.FileInfo, errorChannel chan<- error) {
 	nodes, err := os.ReadDir(path)
 	switch {
 	case errors.Is(err, syscall.ENOTDIR):
-		if extension == "" || filepath.Ext(path) == extension {
-			loadFromFile(path, index, list, errorChannel)
+		if matchesExtension(path) {
+			statFile(path, found, errorChannel)
 		}
 	case err != nil:
 		errorChannel <- err
@@ -345,165 +768,392 @@ func walkPath(path string, index map[Category][]QuestionId, list map[QuestionId]
 			fullPath := filepath.Join(path, node.Name())
 
 			switch {
-			case !node.IsDir() && (extension == "" || filepath.Ext(node.Name()) == extension):
-				loadFromFile(fullPath, index, list, errorChannel)
+			case !node.IsDir() && matchesExtension(node.Name()):
+				statFile(fullPath, found, errorChannel)
 			case node.IsDir() && recursive:
-				walkPath(fullPath, index, list, errorChannel)
+				walkPath(fullPath, found, errorChannel)
 			}
 		}
 	}
+}
+
+func statFile(path string, found map[string]os.FileInfo, errorChannel chan<- error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		errorChannel <- err
 
-	return triviaIndex, categoryIndex
+		return
+	}
+
+	found[path] = info
 }
 
-func loadFromFile(path string, index map[Category][]QuestionId, list map[QuestionId]*Trivia, errorChannel chan<- error) {
-	f, err := os.Open(path)
+func loadFromFile(path string, errorChannel chan<- error) []entry {
+	skip := func(line int, reason string) {
+		slog.Debug("Skipped invalid entry",
+			"file", path,
+			"line", line,
+			"reason", reason)
+	}
+
+	strip := func(line int, field string, removed []string) {
+		slog.Warn("Stripped disallowed HTML",
+			"file", path,
+			"line", line,
+			"field", field,
+			"removed", strings.Join(removed, ", "))
+	}
+
+	entries, err := parseFile(path, skip, strip)
 	if err != nil {
 		errorChannel <- err
 	}
-	defer func() {
-		err = f.Close()
-		if err != nil {
-			errorChannel <- err
-		}
-	}()
 
-	s := bufio.NewScanner(f)
-	b := make([]byte, 0, 64*1024)
-	s.Buffer(b, 1024*1024)
-	s.Split(bufio.ScanLines)
+	return entries
+}
 
-	l := 0
+// parseFile returns the valid entries in a question file, calling skip for every
+// entry which is rejected, along with any error which prevented reading the rest of the file.
+// If --html is enabled, strip is called for every field from which disallowed HTML was removed.
+func parseFile(path string, skip func(line int, reason string), strip func(line int, field string, removed []string)) ([]entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
 
-	for s.Scan() {
-		l += 1
+	entries, err := getLoader(path)(f, skip)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
 
-		line := s.Text()
+	valid := make([]entry, 0, len(entries))
+
+	for _, e := range entries {
+		t := e.trivia
+
+		t.Question = strings.TrimSpace(t.Question)
+		t.Category = parseCategory(t.Category.String())
+
+		answer, alternatives := splitAnswer(t.Answer)
+		t.Answer = answer
+		t.Alternatives = append(alternatives, t.Alternatives...)
+
+		difficulty, known := parseDifficulty(string(t.Difficulty))
+		if !known {
+			skip(e.line, fmt.Sprintf("unknown difficulty `%s`", t.Difficulty))
 
-		if line == "" {
 			continue
 		}
 
-		var question, answer string
-		var category Category
+		t.Difficulty = difficulty
 
-		split := strings.Split(line, "|")
+		if html {
+			sanitizeTrivia(t, allowlist, func(field string, removed []string) {
+				strip(e.line, field, removed)
+			})
+
+			t.Question = strings.TrimSpace(t.Question)
+			t.Answer = strings.TrimSpace(t.Answer)
+		}
 
 		switch {
-		case len(split) == 2 || len(split) == 3 && split[2] == "":
-			question = strings.TrimSpace(split[0])
-			answer = strings.TrimSpace(split[1])
-			category = "Uncategorized"
-		case len(split) == 3:
-			question = strings.TrimSpace(split[0])
-			answer = strings.TrimSpace(split[1])
-			category = Category(strings.TrimSpace(split[2]))
-		default:
-			if verbose {
-				fmt.Printf("%s | Skipped invalid entry at %s:%d\n",
-					time.Now().Format(logDate),
-					path,
-					l)
-			}
+		case t.Question == "":
+			skip(e.line, "empty question")
 
 			continue
+		case t.Answer == "":
+			skip(e.line, "empty answer")
+
+			continue
+		case t.Category == "":
+			t.Category = "Uncategorized"
 		}
 
-		t := &Trivia{question, answer, category}
+		if markdown {
+			renderTrivia(t)
+		}
 
-		id := t.getId()
+		valid = append(valid, e)
+	}
 
-		_, exists := list[id]
-		if exists {
-			if verbose {
-				fmt.Printf("%s | Skipped duplicate entry at %s:%d\n",
-					time.Now().Format(logDate),
-					path,
-					l)
+	return valid, err
+}
+
+// patch updates the index in place, removing the questions from files which
+// have changed or no longer exist, then adding the questions from parsed files.
+// Questions appearing in several files are served from the first file by path,
+// so that the result does not depend on the order in which files were loaded.
+func (q *Questions) patch(found map[string]os.FileInfo, parsed map[string][]entry) {
+	touched := map[Category]bool{}
+	deleted := map[QuestionId]bool{}
+	affected := map[QuestionId]bool{}
+
+	for path, f := range q.files {
+		_, exists := found[path]
+		_, changed := parsed[path]
+
+		if exists && !changed {
+			continue
+		}
+
+		for id := range f.trivia {
+			q.sources[id] = slices.DeleteFunc(q.sources[id], func(source string) bool {
+				return source == path
+			})
+
+			affected[id] = true
+		}
+
+		delete(q.files, path)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(parsed)) {
+		entries := parsed[path]
+
+		f := &File{
+			modTime: found[path].ModTime(),
+			size:    found[path].Size(),
+			trivia:  make(map[QuestionId]*Trivia, len(entries)),
+		}
+
+		for _, e := range entries {
+			id := e.trivia.getId()
+
+			if _, exists := f.trivia[id]; exists {
+				slog.Debug("Skipped duplicate entry",
+					"file", path,
+					"line", e.line)
+
+				continue
 			}
 
+			f.trivia[id] = e.trivia
+
+			i, _ := slices.BinarySearch(q.sources[id], path)
+			q.sources[id] = slices.Insert(q.sources[id], i, path)
+
+			affected[id] = true
+		}
+
+		q.files[path] = f
+	}
+
+	for id := range affected {
+		previous := q.list[id]
+
+		sources := q.sources[id]
+		if len(sources) < 1 {
+			delete(q.sources, id)
+
+			if previous != nil {
+				touched[previous.Category] = true
+				deleted[id] = true
+
+				q.unindexTerms(id, previous)
+				q.trackMedia(previous, -1)
+
+				delete(q.list, id)
+			}
+
+			continue
+		}
+
+		t := q.files[sources[0]].trivia[id]
+		if t == previous {
+			continue
+		}
+
+		// Questions which were already loaded from another file are still present in the index
+		if previous != nil {
+			q.unindexTerms(id, previous)
+			q.trackMedia(previous, -1)
+		} else {
+			q.index[t.Category] = append(q.index[t.Category], id)
+		}
+
+		q.list[id] = t
+
+		q.indexTerms(id, t)
+		q.trackMedia(t, 1)
+
+		touched[t.Category] = true
+	}
+
+	for category := range touched {
+		ids := slices.DeleteFunc(q.index[category], func(id QuestionId) bool {
+			return deleted[id]
+		})
+
+		if len(ids) < 1 {
+			delete(q.index, category)
+			delete(q.buckets, category)
+
 			continue
 		}
 
-		index[category] = append(index[category], id)
+		slices.Sort(ids)
 
-		list[id] = t
+		q.index[category] = ids
+		q.buckets[category] = newBucket(ids, q.list)
+	}
+
+	if len(touched) > 0 {
+		sorted := make([]string, 0, len(q.index))
+
+		for category := range q.index {
+			sorted = append(sorted, category.String())
+		}
+
+		slices.Sort(sorted)
+
+		q.sorted = sorted
+
+		clear(q.expansions)
 	}
 }
 
-func loadQuestions(paths []string, questions *Questions, errorChannel chan<- error) (int, int) {
+// loadQuestions rebuilds the question list and color mappings, swapping both in together
+func loadQuestions(paths []string, questions *Questions, metrics *Metrics, errorChannel chan<- error) Stats {
 	startTime := time.Now()
 
-	index := map[Category][]QuestionId{}
-	list := map[QuestionId]*Trivia{}
+	questions.loading.Lock()
+	defer questions.loading.Unlock()
+
+	// Errors are passed through a local channel, so that rebuilds which
+	// encountered any can be counted as failures
+	failed := false
+
+	proxy := make(chan error)
+	done := make(chan struct{})
+
+	go func(errorChannel chan<- error) {
+		for err := range proxy {
+			failed = true
+
+			errorChannel <- err
+		}
+
+		close(done)
+	}(errorChannel)
+
+	defer func() {
+		close(proxy)
+		<-done
+
+		metrics.reloaded(time.Since(startTime), failed)
+	}()
+
+	errorChannel = proxy
+
+	found := map[string]os.FileInfo{}
 
 	for i := range paths {
-		walkPath(paths[i], index, list, errorChannel)
+		walkPath(paths[i], found, errorChannel)
 	}
 
-	if len(index) < 1 || len(list) < 1 {
-		fmt.Printf("%s | No supported files found.\n", startTime.Format(logDate))
+	// Only files which are new or have been modified since the last load are parsed.
+	// Files is only ever modified while loading is held, so no read lock is needed.
+	parsed := map[string][]entry{}
+
+	for path, info := range found {
+		f, exists := questions.files[path]
+		if exists && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+			continue
+		}
+
+		parsed[path] = loadFromFile(path, errorChannel)
+
+		attachMedia(paths, path, parsed[path])
 	}
 
-	for i := range index {
-		slices.Sort(index[i])
+	removed := 0
+
+	for path := range questions.files {
+		if _, exists := found[path]; !exists {
+			removed++
+		}
+	}
+
+	// If the colors file cannot be read, the previous mappings are kept
+	colors, rejected, err := loadColors(colorsFile)
+	if err != nil {
+		errorChannel <- err
 	}
 
 	questions.mu.Lock()
-	questions.index = index
-	questions.list = list
-	categoryCount := len(questions.index)
-	triviaCount := len(questions.list)
+	questions.patch(found, parsed)
+	if colors != nil {
+		questions.colors = colors
+	}
+	stats := Stats{
+		questions:  len(questions.list),
+		categories: len(questions.index),
+		colors:     len(questions.colors),
+		rejected:   rejected,
+	}
 	questions.mu.Unlock()
 
-	fmt.Printf("%s | Loaded %d questions across %d categories in %s\n",
-		startTime.Format(logDate),
-		triviaCount,
-		categoryCount,
-		time.Since(startTime))
+	stats.duration = time.Since(startTime)
+
+	if stats.questions < 1 {
+		slog.Warn("No supported files found")
+	}
+
+	slog.Debug("Parsed question files",
+		"modified", len(parsed),
+		"removed", removed)
+
+	slog.Info("Loaded questions",
+		"questions", stats.questions,
+		"categories", stats.categories,
+		"colors", stats.colors,
+		"duration", stats.duration)
 
-	return triviaCount, categoryCount
+	return stats
 }
 
-func serveHome(questions *Questions) httprouter.Handle {
+func serveHome(questions *Questions, sessions *Sessions) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		var id QuestionId
+
+		if sessions != nil {
+			id = sessions.next(w, r, questions)
+		} else {
+			id = questions.getRandomId(r)
+		}
+
 		newUrl := fmt.Sprintf("%s//%s/q/%s",
 			r.URL.Scheme,
 			r.Host,
-			questions.getRandomId(r),
+			id,
 		)
 
 		http.Redirect(w, r, newUrl, http.StatusSeeOther)
 	}
 }
 
-func serveQuestion(questions *Questions, colors map[Category]Color, tpl *template.Template, errorChannel chan<- error) httprouter.Handle {
+func serveQuestion(questions *Questions, tpl *template.Template, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
-
 		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
 
-		if verbose {
-			fmt.Printf("%s | %s => %s\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI)
-		}
-
-		color := DefaultColor
+		color := ErrorColor
 
 		q := questions.getTrivia(QuestionId(path.Base(r.URL.Path)))
 
-		if q == nil || len(questions.index) < 1 {
-			color = ErrorColor
+		loaded := questions.CategoryCount() > 0
+
+		if q != nil && loaded {
+			color = questions.getColor(q.Category)
+		}
+
+		if q != nil {
+			metrics.questionServed(q.Category)
 		} else {
-			c, exists := colors[q.Category]
-			if exists {
-				color = c
-			}
+			metrics.questionNotFound()
 		}
 
-		w.Header().Set("Content-Security-Policy", fmt.Sprintf("default-src 'self'; style-src-elem 'self' 'sha256-%s'", color.Hash))
+		w.Header().Set("Content-Security-Policy", fmt.Sprintf("default-src 'self'; img-src 'self'; media-src 'self'; style-src-elem 'self' 'sha256-%s'", color.Hash))
 
 		securityHeaders(w)
 
@@ -525,7 +1175,7 @@ func serveQuestion(questions *Questions, colors map[Category]Color, tpl *templat
 		}
 
 		switch {
-		case len(questions.index) < 1:
+		case !loaded:
 			question.Question = "How do I load questions into Trivia?"
 			question.Answer = template.HTML("See <a id=\"help\" href=\"https://github.com/Seednode/trivia?tab=readme-ov-file#file-format\">the docs</a>.")
 			question.Category = "Usage"
@@ -533,18 +1183,55 @@ func serveQuestion(questions *Questions, colors map[Category]Color, tpl *templat
 			question.Question = "Are you sure this URL is correct?"
 			question.Answer = template.HTML("If not, please go back to the <a id=\"help\" href=\"/\">homepage</a> and try again.")
 			question.Category = "Error"
-		case html:
+		case html || markdown:
 			question.Question = template.HTML(q.Question)
-			question.Answer = template.HTML(q.Answer)
+			question.Answer = template.HTML(q.displayedAnswer())
+			question.Explanation = template.HTML(q.Explanation)
 			question.Category = q.Category
+			question.Media = q.attachment
+			question.Choices = getChoices(q)
 		default:
 			question.Question = q.Question
-			question.Answer = q.Answer
+			question.Answer = q.displayedAnswer()
+			question.Explanation = q.Explanation
 			question.Category = q.Category
+			question.Media = q.attachment
+			question.Choices = getChoices(q)
+		}
+
+		if q != nil && loaded {
+			question.Source = q.Source
+			question.SourceLink = isSourceLink(q.Source)
+		}
+
+		if checkAnswers && q != nil && loaded {
+			question.Answer = ""
+			question.Explanation = ""
+			question.Source = ""
+			question.SourceLink = false
+			question.Check = true
+
+			for i := range question.Choices {
+				question.Choices[i].Correct = false
+			}
+		}
+
+		links := []string{}
+
+		if searchEnabled {
+			links = append(links, "<a href=\"/search\">Search</a>")
+		}
+
+		if roomsEnabled {
+			links = append(links, "<a href=\"/rooms\">Present</a>")
 		}
 
 		if settings {
-			question.Settings = template.HTML("<p id=\"settings-link\"><a href=\"/settings\">Settings</a></p>")
+			links = append(links, "<a href=\"/settings\">Settings</a>")
+		}
+
+		if len(links) > 0 {
+			question.Settings = template.HTML("<p id=\"settings-link\">" + strings.Join(links, " | ") + "</p>")
 		}
 
 		err := tpl.Execute(w, question)
@@ -565,7 +1252,7 @@ func serveCategories(questions *Questions, errorChannel chan<- error) httprouter
 	}
 }
 
-func registerQuestions(mux *httprouter.Router, colors map[Category]Color, questions *Questions, errorChannel chan<- error) {
+func registerQuestions(mux *Router, questions *Questions, sessions *Sessions, metrics *Metrics, errorChannel chan<- error) {
 	template, err := template.New("question").Parse(getQuestionTemplate())
 	if err != nil {
 		errorChannel <- err
@@ -573,7 +1260,11 @@ func registerQuestions(mux *httprouter.Router, colors map[Category]Color, questi
 		return
 	}
 
-	mux.GET("/", serveHome(questions))
-	mux.GET("/q/*id", serveQuestion(questions, colors, template, errorChannel))
+	mux.GET("/", serveHome(questions, sessions))
+	mux.GET("/q/*id", serveQuestion(questions, template, metrics, errorChannel))
 	mux.GET("/categories", serveCategories(questions, errorChannel))
+
+	if checkAnswers {
+		mux.POST("/check/:id", serveCheck(questions, metrics, errorChannel))
+	}
 }
@@ -0,0 +1,174 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// snapshot describes every question served, along with the contents of each category's buckets
+func snapshot(q *Questions) map[string]string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	s := map[string]string{}
+
+	for id, t := range q.list {
+		s[id.String()] = fmt.Sprintf("%s|%s|%s|%s|%s|%v", t.Question, t.Answer, t.Category, t.Difficulty, t.Explanation, t.Incorrect)
+	}
+
+	for category, b := range q.buckets {
+		s[category.String()] = fmt.Sprint(b.ids, b.ends)
+	}
+
+	return s
+}
+
+func load(t *testing.T, q *Questions, paths []string) {
+	t.Helper()
+
+	errorChannel := make(chan error, len(paths))
+
+	loadQuestions(paths, q, nil, errorChannel)
+
+	select {
+	case err := <-errorChannel:
+		t.Fatal(err)
+	default:
+	}
+}
+
+func TestPatchMatchesFullLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	a := filepath.Join(dir, "a.trivia")
+	b := filepath.Join(dir, "b.trivia")
+	c := filepath.Join(dir, "c.trivia")
+
+	modified := time.Now()
+
+	write := func(path, contents string) {
+		t.Helper()
+
+		err := os.WriteFile(path, []byte(contents), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Files are only reloaded if their size or modification time changes
+		modified = modified.Add(time.Second)
+
+		err = os.Chtimes(path, modified, modified)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name  string
+		apply func()
+	}{
+		{"initial", func() {
+			write(a, "Q|A|C|explanation=old\nOther|B|C\n")
+			write(b, "Q|A|C|explanation=old\nThird|D|E|difficulty=easy\n")
+			write(c, "Third|D|E|difficulty=hard\n")
+		}},
+		{"edit later copy", func() { write(b, "Q|A|C|explanation=new\nThird|D|E|difficulty=easy\n") }},
+		{"edit served copy", func() { write(a, "Q|A|C|explanation=new\nOther|B|C\n") }},
+		{"change difficulty", func() { write(b, "Q|A|C|explanation=new\nThird|D|E|difficulty=medium\n") }},
+		{"remove served copy", func() { os.Remove(a) }},
+		{"restore served copy", func() { write(a, "Q|A|C|explanation=first|incorrect=X\n") }},
+		{"remove every copy", func() {
+			write(b, "Other|B|C\n")
+			os.Remove(a)
+			os.Remove(c)
+		}},
+	}
+
+	incremental := newQuestions()
+
+	for _, step := range steps {
+		step.apply()
+
+		paths := []string{}
+
+		for _, path := range []string{a, b, c} {
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+			}
+		}
+
+		load(t, incremental, paths)
+
+		full := newQuestions()
+
+		load(t, full, paths)
+
+		got, want := snapshot(incremental), snapshot(full)
+
+		if !maps.Equal(got, want) {
+			t.Errorf("%s: incremental load differs from full load\ngot:  %v\nwant: %v", step.name, got, want)
+		}
+	}
+}
+
+func TestPatchServesFirstFileByPath(t *testing.T) {
+	dir := t.TempDir()
+
+	paths := []string{}
+
+	for _, name := range []string{"d", "b", "a", "c"} {
+		path := filepath.Join(dir, name+".trivia")
+
+		err := os.WriteFile(path, []byte("Q|A|C|explanation="+name+"\n"), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	for range 10 {
+		q := newQuestions()
+
+		load(t, q, paths)
+
+		for _, trivia := range q.list {
+			if trivia.Explanation != "a" {
+				t.Fatalf("served explanation %q, want %q", trivia.Explanation, "a")
+			}
+		}
+	}
+}
+
+func TestParseDifficultySet(t *testing.T) {
+	tests := []struct {
+		list    string
+		enabled []Difficulty
+	}{
+		{"", Difficulties[:]},
+		{" , ", Difficulties[:]},
+		{"easy,hard", []Difficulty{Easy, Hard}},
+		{"easy,bogus", []Difficulty{Easy}},
+		{"bogus", nil},
+		{"bogus,,other", nil},
+	}
+
+	for _, test := range tests {
+		set := parseDifficultySet(test.list)
+
+		for _, d := range Difficulties {
+			if want := slices.Contains(test.enabled, d); set.has(d) != want {
+				t.Errorf("parseDifficultySet(%q).has(%s) = %t, want %t", test.list, d, !want, want)
+			}
+		}
+	}
+}
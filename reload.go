@@ -6,13 +6,17 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
-func registerReloadInterval(paths []string, questions *Questions, quit <-chan struct{}, errorChannel chan<- error) {
+func registerReloadInterval(paths []string, questions *Questions, metrics *Metrics, quit <-chan struct{}, errorChannel chan<- error) {
 	interval, err := time.ParseDuration(reloadInterval)
 	if err != nil {
 		errorChannel <- err
@@ -22,10 +26,7 @@ func registerReloadInterval(paths []string, questions *Questions, quit <-chan st
 
 	ticker := time.NewTicker(interval)
 
-	if verbose {
-		next := time.Now().Add(interval).Truncate(time.Second)
-		fmt.Printf("%s | Next scheduled rebuild will run at %s\n", time.Now().Format(logDate), next.Format(logDate))
-	}
+	slog.Debug("Scheduled rebuild", "next", time.Now().Add(interval).Truncate(time.Second))
 
 	go func() {
 		for {
@@ -33,15 +34,11 @@ func registerReloadInterval(paths []string, questions *Questions, quit <-chan st
 			case <-ticker.C:
 				next := time.Now().Add(interval).Truncate(time.Second)
 
-				if verbose {
-					fmt.Printf("%s | Started scheduled rebuild\n", time.Now().Format(logDate))
-				}
+				slog.Debug("Started scheduled rebuild")
 
-				loadQuestions(paths, questions, errorChannel)
+				loadQuestions(paths, questions, metrics, errorChannel)
 
-				if verbose {
-					fmt.Printf("%s | Next scheduled rebuild will run at %s\n", time.Now().Format(logDate), next.Format(logDate))
-				}
+				slog.Debug("Scheduled rebuild", "next", next)
 			case <-quit:
 				ticker.Stop()
 
@@ -51,30 +48,51 @@ func registerReloadInterval(paths []string, questions *Questions, quit <-chan st
 	}()
 }
 
-func serveReload(paths []string, questions *Questions, errorChannel chan<- error) httprouter.Handle {
-	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
+// registerHangup rebuilds the question list whenever SIGHUP is received, as with POST /reload
+func registerHangup(paths []string, questions *Questions, metrics *Metrics, quit <-chan struct{}, errorChannel chan<- error) {
+	hangup := make(chan os.Signal, 1)
 
-		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+	signal.Notify(hangup, syscall.SIGHUP)
 
-		securityHeaders(w)
+	go func() {
+		for {
+			select {
+			case <-hangup:
+				slog.Info("Received SIGHUP, rebuilding question list")
+
+				loadQuestions(paths, questions, metrics, errorChannel)
+			case <-quit:
+				signal.Stop(hangup)
 
-		if verbose {
-			fmt.Printf("%s | %s => %s\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI)
+				return
+			}
 		}
+	}()
+}
+
+func serveReload(paths []string, questions *Questions, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+
+		securityHeaders(w)
+
+		stats := loadQuestions(paths, questions, metrics, errorChannel)
+
+		response := fmt.Appendf(nil, "Loaded %d questions across %d categories and %d color mappings in %s.\n",
+			stats.questions,
+			stats.categories,
+			stats.colors,
+			stats.duration)
 
-		triviaCount, categoryCount := loadQuestions(paths, questions, errorChannel)
+		if len(stats.rejected) > 0 {
+			response = fmt.Appendf(response, "Rejected %d color mappings:\n", len(stats.rejected))
 
-		fmt.Printf("%s | Loaded %d questions spanning %d categories in %s\n",
-			startTime.Format(logDate),
-			triviaCount,
-			categoryCount,
-			time.Since(startTime))
+			for _, p := range stats.rejected {
+				response = fmt.Appendf(response, "  %s\n", p)
+			}
+		}
 
-		_, err := w.Write(fmt.Appendf(nil, "Loaded %d questions across %d categories in %s.\n", triviaCount, categoryCount, time.Since(startTime)))
+		_, err := w.Write(response)
 		if err != nil {
 			errorChannel <- err
 
@@ -83,6 +101,6 @@ func serveReload(paths []string, questions *Questions, errorChannel chan<- error
 	}
 }
 
-func registerReload(mux *httprouter.Router, paths []string, questions *Questions, errorChannel chan<- error) {
-	mux.POST("/reload", serveReload(paths, questions, errorChannel))
+func registerReload(mux *Router, auth *Auth, paths []string, questions *Questions, metrics *Metrics, errorChannel chan<- error) {
+	mux.POST("/reload", auth.protect(serveReload(paths, questions, metrics, errorChannel)))
 }
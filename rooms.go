@@ -0,0 +1,867 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	roomCodeAlphabet  string        = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	roomCodeLength    int           = 5
+	roomLifetime      time.Duration = 6 * time.Hour
+	emptyRoomLifetime time.Duration = 10 * time.Minute
+	roomHeartbeat     time.Duration = 30 * time.Second
+	maxRooms          int           = 1000
+	maxRoomsPerClient int           = 5
+	maxPlayers        int           = 100
+	maxNicknameLength int           = 24
+	maxTimeLimit      int           = 600
+)
+
+type Player struct {
+	Nickname string
+	Score    int
+	Answered bool
+	Correct  bool
+}
+
+type Subscriber struct {
+	notify chan struct{}
+	host   bool
+	token  string
+}
+
+type Room struct {
+	mu sync.Mutex
+
+	code       string
+	hostToken  string
+	categories []string
+
+	// Creator is the address of the client which created the room
+	creator string
+
+	// Difficulties is the set of enabled difficulties, where the empty set enables every difficulty
+	difficulties DifficultySet
+
+	// Players is a mapping of player tokens to the players themselves
+	players map[string]*Player
+
+	// Asked is the set of questions already used in this room
+	asked map[QuestionId]bool
+
+	current  QuestionId
+	choices  []string
+	round    int
+	revealed bool
+
+	// Deadline is the time after which answers to the current question
+	// are no longer accepted, or zero if the question is untimed
+	deadline time.Time
+	timer    *time.Timer
+
+	subscribers map[*Subscriber]bool
+
+	lastActive time.Time
+}
+
+type Rooms struct {
+	mu sync.Mutex
+
+	// Rooms is a mapping of join codes to rooms
+	rooms map[string]*Room
+}
+
+type PlayerState struct {
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+	Answered bool   `json:"answered"`
+	Correct  *bool  `json:"correct,omitempty"`
+}
+
+type RoomState struct {
+	Code         string        `json:"code"`
+	Round        int           `json:"round"`
+	Question     string        `json:"question,omitempty"`
+	Media        *Media        `json:"media,omitempty"`
+	Category     Category      `json:"category,omitempty"`
+	Color        string        `json:"color,omitempty"`
+	Abbreviation string        `json:"abbreviation,omitempty"`
+	Choices      []string      `json:"choices,omitempty"`
+	Answer       string        `json:"answer,omitempty"`
+	Explanation  string        `json:"explanation,omitempty"`
+	Source       string        `json:"source,omitempty"`
+	Revealed     bool          `json:"revealed"`
+	Closed       bool          `json:"closed"`
+	Remaining    int64         `json:"remaining,omitempty"`
+	Players      []PlayerState `json:"players"`
+	You          *PlayerState  `json:"you,omitempty"`
+}
+
+type RoomPage struct {
+	Version string
+	Theme   string
+	Code    string
+	Role    string
+}
+
+type NextQuestion struct {
+	Seconds int `json:"seconds"`
+}
+
+type LobbyPage struct {
+	Version        string
+	Theme          string
+	CodeLength     int
+	NicknameLength int
+}
+
+func newRooms() *Rooms {
+	return &Rooms{
+		rooms: map[string]*Room{},
+	}
+}
+
+func newRoomCode() string {
+	code := make([]byte, roomCodeLength)
+
+	for i := range code {
+		code[i] = roomCodeAlphabet[mathrand.IntN(len(roomCodeAlphabet))]
+	}
+
+	return string(code)
+}
+
+// create opens a new room, first removing inactive rooms, along with rooms
+// nobody has joined, so that abandoned rooms do not prevent new ones being created
+func (r *Rooms) create(categories []string, difficulties DifficultySet, creator string) (*Room, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := 0
+
+	for code, room := range r.rooms {
+		room.mu.Lock()
+		idle := time.Since(room.lastActive)
+		expired := idle > roomLifetime || len(room.players) < 1 && idle > emptyRoomLifetime
+		room.mu.Unlock()
+
+		switch {
+		case expired:
+			delete(r.rooms, code)
+		case room.creator == creator:
+			created++
+		}
+	}
+
+	if created >= maxRoomsPerClient {
+		return nil, fmt.Errorf("too many active rooms created by this client")
+	}
+
+	if len(r.rooms) >= maxRooms {
+		return nil, fmt.Errorf("too many active rooms")
+	}
+
+	code := newRoomCode()
+	for r.rooms[code] != nil {
+		code = newRoomCode()
+	}
+
+	room := &Room{
+		code:         code,
+		hostToken:    rand.Text(),
+		categories:   categories,
+		creator:      creator,
+		difficulties: difficulties,
+		players:      map[string]*Player{},
+		asked:        map[QuestionId]bool{},
+		subscribers:  map[*Subscriber]bool{},
+		lastActive:   time.Now(),
+	}
+
+	r.rooms[code] = room
+
+	return room, nil
+}
+
+func (r *Rooms) get(code string) *Room {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rooms[strings.ToUpper(strings.TrimSpace(code))]
+}
+
+// broadcast notifies every subscriber that the room state has changed,
+// and must be called with the room lock held
+func (room *Room) broadcast() {
+	room.lastActive = time.Now()
+
+	for s := range room.subscribers {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (room *Room) isHost(r *http.Request) bool {
+	return getCookie(r, "host") == room.hostToken
+}
+
+func (room *Room) getPlayer(r *http.Request) (string, *Player) {
+	token := getCookie(r, "player")
+
+	return token, room.players[token]
+}
+
+// isClosed reports whether answers to the current question are no longer accepted,
+// and must be called with the room lock held
+func (room *Room) isClosed() bool {
+	return room.revealed || !room.deadline.IsZero() && time.Now().After(room.deadline)
+}
+
+// advance moves the room on to a new question it has not yet used, optionally
+// closing answers after the given time limit, and must be called with the room lock held
+func (room *Room) advance(questions *Questions, limit time.Duration) {
+	ids := questions.getEligible(room.categories, room.difficulties)
+
+	remaining := slices.DeleteFunc(ids, func(id QuestionId) bool {
+		return room.asked[id]
+	})
+
+	if len(remaining) < 1 {
+		clear(room.asked)
+
+		remaining = questions.getEligible(room.categories, room.difficulties)
+	}
+
+	room.current = NoQuestion
+	room.choices = nil
+
+	if len(remaining) > 0 {
+		room.current = remaining[mathrand.IntN(len(remaining))]
+		room.asked[room.current] = true
+
+		t := questions.getTrivia(room.current)
+		if t != nil && len(t.Incorrect) > 0 {
+			room.choices = append([]string{t.Answer}, t.Incorrect...)
+
+			mathrand.Shuffle(len(room.choices), func(i, j int) {
+				room.choices[i], room.choices[j] = room.choices[j], room.choices[i]
+			})
+		}
+	}
+
+	room.round++
+	room.revealed = false
+	room.deadline = time.Time{}
+
+	if room.timer != nil {
+		room.timer.Stop()
+	}
+
+	if limit > 0 {
+		room.deadline = time.Now().Add(limit)
+
+		// Let every client know the moment answers close
+		room.timer = time.AfterFunc(limit, func() {
+			room.mu.Lock()
+			room.broadcast()
+			room.mu.Unlock()
+		})
+	}
+
+	for _, player := range room.players {
+		player.Answered = false
+		player.Correct = false
+	}
+}
+
+func escapeContent(s string) string {
+	if html || markdown {
+		return s
+	}
+
+	return template.HTMLEscapeString(s)
+}
+
+// state builds the view of the room for a given subscriber,
+// and must be called with the room lock held
+func (room *Room) state(s *Subscriber, questions *Questions) RoomState {
+	state := RoomState{
+		Code:     room.code,
+		Round:    room.round,
+		Revealed: room.revealed,
+		Closed:   room.isClosed(),
+		Players:  []PlayerState{},
+	}
+
+	if !state.Closed && !room.deadline.IsZero() {
+		state.Remaining = time.Until(room.deadline).Milliseconds()
+	}
+
+	for token, player := range room.players {
+		p := PlayerState{
+			Nickname: player.Nickname,
+			Score:    player.Score,
+			Answered: player.Answered,
+		}
+
+		if room.revealed || s.host || token == s.token {
+			p.Correct = &player.Correct
+		}
+
+		state.Players = append(state.Players, p)
+
+		if token == s.token {
+			state.You = &p
+		}
+	}
+
+	slices.SortFunc(state.Players, func(a, b PlayerState) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Nickname, b.Nickname))
+	})
+
+	t := questions.getTrivia(room.current)
+	if t == nil {
+		return state
+	}
+
+	color := questions.getColor(t.Category)
+
+	state.Question = escapeContent(t.Question)
+	state.Media = t.attachment
+	state.Category = t.Category
+	state.Color = color.Hex
+	state.Abbreviation = color.Abbreviation
+
+	for _, choice := range room.choices {
+		state.Choices = append(state.Choices, escapeContent(choice))
+	}
+
+	if room.revealed || s.host {
+		state.Answer = escapeContent(t.displayedAnswer())
+		state.Explanation = escapeContent(t.Explanation)
+		state.Source = t.Source
+	}
+
+	return state
+}
+
+func getRoomTemplate() string {
+	return `<!DOCTYPE html>
+<html lang="en-US">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <meta name="Description" content="A very basic trivia webapp." />
+    <title>Trivia v{{.Version}}</title>
+    <link rel="stylesheet" href="/css/{{.Theme}}.css" />
+    <link rel="stylesheet" href="/css/trivia.css" />
+    <script src="/js/rooms.js" defer></script>
+    <link rel="apple-touch-icon" sizes="180x180" href="/favicons/apple-touch-icon.webp" />
+    <link rel="icon" type="image/webp" sizes="32x32" href="/favicons/favicon-32x32.webp" />
+    <link rel="icon" type="image/webp" sizes="16x16" href="/favicons/favicon-16x16.webp" />
+    <link rel="manifest" href="/favicons/site.webmanifest" crossorigin="use-credentials" />
+    <link rel="mask-icon" href="/favicons/safari-pinned-tab.svg" color="#5bbad5" />
+    <meta name="msapplication-TileColor" content="#da532c" />
+    <meta name="theme-color" content="#ffffff" />
+  </head>
+  <body data-code="{{.Code}}" data-role="{{.Role}}">
+    <p id="room-code">Room {{.Code}}</p>
+    {{- if eq .Role "host"}}
+    <p id="settings-link"><a href="/rooms/{{.Code}}/display" target="_blank">Open display view</a></p>
+    {{- end}}
+    <p id="round"></p>
+    <p id="timer"></p>
+    <p id="question"></p>
+    <div id="media"></div>
+    <div id="choices"></div>
+    {{- if eq .Role "player"}}
+    <form id="guess-form">
+      <input id="guess" type="text" autocomplete="off" placeholder="Your answer" />
+      <button id="submit-guess" type="submit">Submit</button>
+    </form>
+    <p id="verdict"></p>
+    {{- end}}
+    {{- if eq .Role "host"}}
+    <div class="select-buttons">
+      <label for="time-limit">Time limit
+        <select id="time-limit">
+          <option value="0">None</option>
+          <option value="15">15 seconds</option>
+          <option value="30">30 seconds</option>
+          <option value="60">60 seconds</option>
+          <option value="120">2 minutes</option>
+        </select>
+      </label>
+      <button id="next-question" class="settings-select">Next Question</button>
+      <button id="reveal-answer" class="settings-select">Reveal Answer</button>
+    </div>
+    {{- end}}
+    <div id="answer">
+      <p></p>
+      <p id="explanation"></p>
+      <p id="source"></p>
+    </div>
+    <ol id="scoreboard"></ol>
+    <div class="footer"><p id="category"></p></div>
+  </body>
+</html>`
+}
+
+func getLobbyTemplate() string {
+	return `<!DOCTYPE html>
+<html lang="en-US">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <meta name="Description" content="A very basic trivia webapp." />
+    <title>Trivia v{{.Version}}</title>
+    <link rel="stylesheet" href="/css/{{.Theme}}.css" />
+    <link rel="stylesheet" href="/css/trivia.css" />
+    <link rel="apple-touch-icon" sizes="180x180" href="/favicons/apple-touch-icon.webp" />
+    <link rel="icon" type="image/webp" sizes="32x32" href="/favicons/favicon-32x32.webp" />
+    <link rel="icon" type="image/webp" sizes="16x16" href="/favicons/favicon-16x16.webp" />
+    <link rel="manifest" href="/favicons/site.webmanifest" crossorigin="use-credentials" />
+    <link rel="mask-icon" href="/favicons/safari-pinned-tab.svg" color="#5bbad5" />
+    <meta name="msapplication-TileColor" content="#da532c" />
+    <meta name="theme-color" content="#ffffff" />
+  </head>
+  <body>
+    <p id="settings-link"><a href="/">Back to homepage</a></p>
+    <div class="settings-container">
+      <div class="settings-wrapper">
+        <div class="settings-section">
+          <h2>Host a room</h2>
+          <p>Questions are drawn from your enabled categories and difficulties.</p>
+          <p>The host view shows answers and controls, and links to a display view for projectors.</p>
+        </div>
+        <form method="post" action="/rooms">
+          <button class="settings-submit" type="submit">Create Room</button>
+        </form>
+      </div>
+      <div class="settings-wrapper">
+        <form method="post" action="/rooms">
+          <div class="settings-section">
+            <h2>Join a room</h2>
+            <input name="code" type="text" autocomplete="off" placeholder="Room code" maxlength="{{.CodeLength}}" required />
+            <input name="nickname" type="text" autocomplete="off" placeholder="Nickname" maxlength="{{.NicknameLength}}" required />
+          </div>
+          <button class="settings-submit" type="submit">Join Room</button>
+        </form>
+      </div>
+    </div>
+  </body>
+</html>`
+}
+
+func roomHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; img-src 'self'; media-src 'self';")
+
+	securityHeaders(w)
+}
+
+func serveLobby(tpl *template.Template, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		roomHeaders(w)
+
+		err := tpl.Execute(w, LobbyPage{
+			Version:        ReleaseVersion,
+			Theme:          getTheme(r),
+			CodeLength:     roomCodeLength,
+			NicknameLength: maxNicknameLength,
+		})
+		if err != nil {
+			errorChannel <- err
+		}
+	}
+}
+
+// serveRoomForm creates a new room, or joins an existing one when a code is provided
+func serveRoomForm(rooms *Rooms, questions *Questions) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		code := r.PostFormValue("code")
+
+		if code == "" {
+			// Rooms are limited per client, which is identified by its address
+			creator, _, err := net.SplitHostPort(realIP(r))
+			if err != nil {
+				creator = realIP(r)
+			}
+
+			room, err := rooms.create(getCategories(r, questions), getDifficulties(r), creator)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     "host",
+				Value:    room.hostToken,
+				Path:     "/rooms/" + room.code,
+				MaxAge:   int(roomLifetime.Seconds()),
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+
+			slog.Debug("Created room",
+				"room", room.code,
+				"remote", realIP(r))
+
+			http.Redirect(w, r, "/rooms/"+room.code, http.StatusSeeOther)
+
+			return
+		}
+
+		room := rooms.get(code)
+		if room == nil {
+			http.Error(w, "Room not found", http.StatusNotFound)
+
+			return
+		}
+
+		nickname := strings.TrimSpace(r.PostFormValue("nickname"))
+		if nickname == "" || utf8.RuneCountInString(nickname) > maxNicknameLength {
+			http.Error(w, "Invalid nickname", http.StatusBadRequest)
+
+			return
+		}
+
+		room.mu.Lock()
+		defer room.mu.Unlock()
+
+		for _, player := range room.players {
+			if strings.EqualFold(player.Nickname, nickname) {
+				http.Error(w, "Nickname already taken", http.StatusConflict)
+
+				return
+			}
+		}
+
+		if len(room.players) >= maxPlayers {
+			http.Error(w, "Room is full", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		token := rand.Text()
+
+		room.players[token] = &Player{Nickname: nickname}
+
+		room.broadcast()
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     "player",
+			Value:    token,
+			Path:     "/rooms/" + room.code,
+			MaxAge:   int(roomLifetime.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+
+		slog.Debug("Joined room",
+			"room", room.code,
+			"nickname", nickname,
+			"remote", realIP(r))
+
+		http.Redirect(w, r, "/rooms/"+room.code, http.StatusSeeOther)
+	}
+}
+
+func serveRoom(rooms *Rooms, tpl *template.Template, display bool, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		room := rooms.get(p.ByName("code"))
+		if room == nil {
+			http.Redirect(w, r, "/rooms", http.StatusSeeOther)
+
+			return
+		}
+
+		if p.ByName("code") != room.code {
+			http.Redirect(w, r, strings.Replace(r.URL.Path, p.ByName("code"), room.code, 1), http.StatusSeeOther)
+
+			return
+		}
+
+		role := ""
+
+		room.mu.Lock()
+		_, player := room.getPlayer(r)
+		switch {
+		case display:
+			role = "display"
+		case room.isHost(r):
+			role = "host"
+		case player != nil:
+			role = "player"
+		}
+		room.mu.Unlock()
+
+		if role == "" {
+			http.Redirect(w, r, "/rooms", http.StatusSeeOther)
+
+			return
+		}
+
+		roomHeaders(w)
+
+		err := tpl.Execute(w, RoomPage{
+			Version: ReleaseVersion,
+			Theme:   getTheme(r),
+			Code:    room.code,
+			Role:    role,
+		})
+		if err != nil {
+			errorChannel <- err
+		}
+	}
+}
+
+func serveRoomEvents(rooms *Rooms, questions *Questions, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		room := rooms.get(p.ByName("code"))
+		if room == nil {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		// Clients which are neither the host nor a player receive the public display view
+		display := r.URL.Query().Get("role") == "display"
+
+		room.mu.Lock()
+		s := &Subscriber{
+			notify: make(chan struct{}, 1),
+			host:   room.isHost(r) && !display,
+		}
+		if !display {
+			s.token, _ = room.getPlayer(r)
+		}
+		room.subscribers[s] = true
+		room.mu.Unlock()
+
+		defer func() {
+			room.mu.Lock()
+			delete(room.subscribers, s)
+			room.mu.Unlock()
+		}()
+
+		// Event streams outlive the server-wide write timeout
+		rc := http.NewResponseController(w)
+
+		err := rc.SetWriteDeadline(time.Time{})
+		if err != nil {
+			errorChannel <- err
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		securityHeaders(w)
+
+		s.notify <- struct{}{}
+
+		heartbeat := time.NewTicker(roomHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				_, err = io.WriteString(w, ": heartbeat\n\n")
+			case <-s.notify:
+				room.mu.Lock()
+				state := room.state(s, questions)
+				room.mu.Unlock()
+
+				var data []byte
+
+				data, err = json.Marshal(state)
+				if err != nil {
+					errorChannel <- err
+
+					return
+				}
+
+				_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+
+			if err == nil {
+				err = rc.Flush()
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func serveRoomAnswer(rooms *Rooms, questions *Questions, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		room := rooms.get(p.ByName("code"))
+		if room == nil {
+			writeJson(w, http.StatusNotFound, ApiError{"room not found"}, errorChannel)
+
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, 64*1024))
+		if err != nil {
+			errorChannel <- err
+
+			return
+		}
+
+		var guess Guess
+
+		err = json.Unmarshal(data, &guess)
+		if err != nil {
+			writeJson(w, http.StatusBadRequest, ApiError{"invalid guess"}, errorChannel)
+
+			return
+		}
+
+		room.mu.Lock()
+		defer room.mu.Unlock()
+
+		_, player := room.getPlayer(r)
+
+		t := questions.getTrivia(room.current)
+
+		switch {
+		case player == nil:
+			writeJson(w, http.StatusForbidden, ApiError{"not a player in this room"}, errorChannel)
+
+			return
+		case t == nil || room.isClosed() || player.Answered:
+			writeJson(w, http.StatusConflict, ApiError{"answers are closed"}, errorChannel)
+
+			return
+		}
+
+		player.Answered = true
+
+		if len(room.choices) > 0 {
+			// Options are picked by position, so that only the correct one can score
+			player.Correct = guess.Choice != nil && *guess.Choice >= 0 && *guess.Choice < len(room.choices) &&
+				room.choices[*guess.Choice] == t.Answer
+		} else {
+			player.Correct = checkAnswer(t, guess.Guess)
+		}
+
+		if player.Correct {
+			player.Score++
+		}
+
+		room.broadcast()
+
+		writeJson(w, http.StatusOK, Verdict{Correct: player.Correct}, errorChannel)
+	}
+}
+
+func serveRoomHost(rooms *Rooms, questions *Questions, reveal bool, metrics *Metrics, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		room := rooms.get(p.ByName("code"))
+		if room == nil {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		room.mu.Lock()
+		defer room.mu.Unlock()
+
+		if !room.isHost(r) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		var next NextQuestion
+
+		if !reveal {
+			data, err := io.ReadAll(io.LimitReader(r.Body, 64*1024))
+			if err != nil {
+				errorChannel <- err
+
+				return
+			}
+
+			if len(data) > 0 && json.Unmarshal(data, &next) != nil {
+				http.Error(w, "Invalid request", http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		if reveal {
+			room.revealed = true
+		} else {
+			room.advance(questions, time.Duration(min(max(next.Seconds, 0), maxTimeLimit))*time.Second)
+
+			if t := questions.getTrivia(room.current); t != nil {
+				metrics.questionServed(t.Category)
+			}
+		}
+
+		room.broadcast()
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func registerRooms(mux *Router, questions *Questions, metrics *Metrics, errorChannel chan<- error) {
+	lobby, err := template.New("lobby").Parse(getLobbyTemplate())
+	if err != nil {
+		errorChannel <- err
+
+		return
+	}
+
+	page, err := template.New("room").Parse(getRoomTemplate())
+	if err != nil {
+		errorChannel <- err
+
+		return
+	}
+
+	rooms := newRooms()
+
+	mux.GET("/rooms", serveLobby(lobby, errorChannel))
+	mux.POST("/rooms", serveRoomForm(rooms, questions))
+	mux.GET("/rooms/:code", serveRoom(rooms, page, false, errorChannel))
+	mux.GET("/rooms/:code/display", serveRoom(rooms, page, true, errorChannel))
+	mux.GET("/rooms/:code/events", serveRoomEvents(rooms, questions, errorChannel))
+	mux.POST("/rooms/:code/answers", serveRoomAnswer(rooms, questions, errorChannel))
+	mux.POST("/rooms/:code/next", serveRoomHost(rooms, questions, false, metrics, errorChannel))
+	mux.POST("/rooms/:code/reveal", serveRoomHost(rooms, questions, true, metrics, errorChannel))
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoomsCreateLimits(t *testing.T) {
+	rooms := newRooms()
+
+	for range maxRoomsPerClient {
+		if _, err := rooms.create(nil, 0, "192.0.2.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := rooms.create(nil, 0, "192.0.2.1"); err == nil {
+		t.Error("created more rooms than allowed for a single client")
+	}
+
+	if _, err := rooms.create(nil, 0, "192.0.2.2"); err != nil {
+		t.Errorf("another client could not create a room: %v", err)
+	}
+
+	// Rooms nobody has joined expire early, which frees up the client's allowance
+	for _, room := range rooms.rooms {
+		room.lastActive = time.Now().Add(-emptyRoomLifetime - time.Minute)
+	}
+
+	joined, err := rooms.create(nil, 0, "192.0.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rooms.rooms) != 1 || rooms.rooms[joined.code] != joined {
+		t.Errorf("%d rooms remain after expiring empty rooms, want 1", len(rooms.rooms))
+	}
+
+	joined.players["token"] = &Player{Nickname: "player"}
+	joined.lastActive = time.Now().Add(-emptyRoomLifetime - time.Minute)
+
+	if _, err := rooms.create(nil, 0, "192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if rooms.rooms[joined.code] == nil {
+		t.Error("room with players expired before the room lifetime")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// The contents of these elements are removed along with the elements themselves, rather than
+// being kept as text. Apart from template, the tokenizer reads their contents as a single
+// unparsed token, which a browser may still parse as markup, so they can never be allowed.
+var rawTextElements = map[string]bool{
+	"iframe":    true,
+	"noembed":   true,
+	"noframes":  true,
+	"noscript":  true,
+	"plaintext": true,
+	"script":    true,
+	"style":     true,
+	"template":  true,
+	"textarea":  true,
+	"title":     true,
+	"xmp":       true,
+}
+
+// URL attributes may only use these schemes, or be relative
+var safeSchemes = []string{"http", "https", "mailto"}
+
+// allowlist is a mapping of the tags allowed by --html-allow to their allowed attributes
+var allowlist = map[string][]string{}
+
+// parseAllowlist parses the --html-allow entries, each of which is a tag name
+// optionally followed by colon-separated attribute names, e.g. a:href:title
+func parseAllowlist(entries []string) (map[string][]string, error) {
+	allowed := map[string][]string{}
+
+	for _, entry := range entries {
+		split := strings.Split(strings.ToLower(strings.TrimSpace(entry)), ":")
+
+		if split[0] == "" || slices.Contains(split, "") {
+			return nil, fmt.Errorf("invalid html allowlist entry %q", entry)
+		}
+
+		if rawTextElements[split[0]] {
+			return nil, fmt.Errorf("html allowlist entry %q cannot be allowed, as its contents are not sanitized", entry)
+		}
+
+		allowed[split[0]] = append(allowed[split[0]], split[1:]...)
+	}
+
+	return allowed, nil
+}
+
+// isSafeUrl reports whether a URL is relative or uses one of the safe schemes
+func isSafeUrl(value string) bool {
+	scheme, _, found := strings.Cut(strings.TrimSpace(value), ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	return slices.Contains(safeSchemes, strings.ToLower(scheme))
+}
+
+// sanitizeHtml removes every tag, attribute, and comment not present in the allowlist, returning
+// the sanitized text along with a description of each removal. Text is returned unchanged if
+// nothing is removed, so that sanitizing does not change the identifiers of questions.
+func sanitizeHtml(text string, allowed map[string][]string) (string, []string) {
+	z := nethtml.NewTokenizer(strings.NewReader(text))
+
+	var b strings.Builder
+
+	removed := []string{}
+
+	remove := func(description string) {
+		if !slices.Contains(removed, description) {
+			removed = append(removed, description)
+		}
+	}
+
+	// Skipping is the name of the raw text element whose contents are being removed
+	skipping := ""
+
+	for {
+		tokenType := z.Next()
+
+		if tokenType == nethtml.ErrorToken {
+			break
+		}
+
+		// Text is written as it appears in the input, since Token unescapes entities
+		raw := string(z.Raw())
+
+		token := z.Token()
+
+		if skipping != "" {
+			if tokenType == nethtml.EndTagToken && token.Data == skipping {
+				skipping = ""
+			}
+
+			continue
+		}
+
+		switch tokenType {
+		case nethtml.TextToken:
+			b.WriteString(raw)
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			attributes, exists := allowed[token.Data]
+			if !exists {
+				remove(fmt.Sprintf("<%s>", token.Data))
+
+				// Browsers ignore the self-closing flag on these elements, as does the tokenizer
+				if rawTextElements[token.Data] {
+					skipping = token.Data
+				}
+
+				continue
+			}
+
+			b.WriteString("<" + token.Data)
+
+			for _, a := range token.Attr {
+				if !slices.Contains(attributes, a.Key) || (a.Key == "href" || a.Key == "src") && !isSafeUrl(a.Val) {
+					remove(fmt.Sprintf("%s attribute of <%s>", a.Key, token.Data))
+
+					continue
+				}
+
+				fmt.Fprintf(&b, " %s=\"%s\"", a.Key, nethtml.EscapeString(a.Val))
+			}
+
+			if tokenType == nethtml.SelfClosingTagToken {
+				b.WriteString(" />")
+			} else {
+				b.WriteString(">")
+			}
+		case nethtml.EndTagToken:
+			if _, exists := allowed[token.Data]; exists {
+				b.WriteString("</" + token.Data + ">")
+			}
+		case nethtml.CommentToken:
+			remove("comment")
+		case nethtml.DoctypeToken:
+			remove("doctype")
+		}
+	}
+
+	if len(removed) < 1 {
+		return text, removed
+	}
+
+	return b.String(), removed
+}
+
+// sanitizeTrivia sanitizes every field of a question which may contain HTML,
+// calling strip with the name of each field from which content was removed
+func sanitizeTrivia(t *Trivia, allowed map[string][]string, strip func(field string, removed []string)) {
+	sanitize := func(field string, text *string) {
+		clean, removed := sanitizeHtml(*text, allowed)
+		if len(removed) > 0 {
+			*text = clean
+
+			strip(field, removed)
+		}
+	}
+
+	sanitize("question", &t.Question)
+	sanitize("answer", &t.Answer)
+	sanitize("explanation", &t.Explanation)
+
+	for i := range t.Alternatives {
+		sanitize("alternatives", &t.Alternatives[i])
+	}
+
+	for i := range t.Incorrect {
+		sanitize("incorrect", &t.Incorrect[i])
+	}
+}
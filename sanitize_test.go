@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import "testing"
+
+func TestSanitizeHtml(t *testing.T) {
+	allowed, err := parseAllowlist([]string{"u", "em", "strong", "sup", "sub", "br", "code", "ruby", "rt", "rp", "a:href"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain text", "What is 2 &lt; 3?", "What is 2 &lt; 3?"},
+		{"allowed tags", "<em>Hello</em>, <strong>world</strong><br>", "<em>Hello</em>, <strong>world</strong><br>"},
+		{"disallowed tag", "<b>bold</b>", "bold"},
+		{"disallowed attribute", "<em onclick=\"alert(1)\">x</em>", "<em>x</em>"},
+		{"unsafe link", "<a href=\"javascript:alert(1)\">x</a>", "<a>x</a>"},
+		{"safe link", "<a href=\"https://example.com\">x</a>", "<a href=\"https://example.com\">x</a>"},
+		{"comment", "a<!-- <script> -->b", "ab"},
+		{"script", "a<script>alert(1)</script>b", "ab"},
+		{"style", "a<style>*{}</style>b", "ab"},
+		{"template", "a<template><em>x</em></template>b", "ab"},
+		{"textarea", "a<textarea><script>alert(1)</script></textarea>b", "ab"},
+		{"title", "a<title><script>alert(1)</script></title>b", "ab"},
+		{"xmp", "a<xmp><script>alert(1)</script></xmp>b", "ab"},
+		{"noscript", "a<noscript><meta http-equiv=\"refresh\" content=\"0;url=https://evil.example\"></noscript>b", "ab"},
+		{"iframe", "a<iframe><script>alert(1)</script></iframe>b", "ab"},
+		{"noembed", "a<noembed><script>alert(1)</script></noembed>b", "ab"},
+		{"noframes", "a<noframes><script>alert(1)</script></noframes>b", "ab"},
+		{"plaintext", "a<plaintext><form action=https://evil.example><button>x</button>", "a"},
+		{"self-closing xmp", "a<xmp/><script>alert(1)</script></xmp>b", "ab"},
+		{"uppercase xmp", "a<XMP><script>alert(1)</script></XMP>b", "ab"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := sanitizeHtml(test.text, allowed)
+			if got != test.want {
+				t.Errorf("sanitizeHtml(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAllowlistRejectsRawTextElements(t *testing.T) {
+	for _, tag := range []string{"script", "xmp", "noscript", "plaintext", "textarea"} {
+		if _, err := parseAllowlist([]string{tag}); err == nil {
+			t.Errorf("parseAllowlist(%q) succeeded, want error", tag)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"cmp"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const maxSearchResults int = 100
+
+type SearchResult struct {
+	Id       QuestionId `json:"id"`
+	Question string     `json:"question"`
+	Category Category   `json:"category"`
+}
+
+type SearchLink struct {
+	Id       QuestionId
+	Question any
+	Category Category
+}
+
+type SearchPage struct {
+	Version string
+	Theme   string
+	Query   string
+	Results []SearchLink
+	More    bool
+}
+
+// searchTerms splits text into the normalized words used by the search index,
+// so that queries match regardless of case, accents, or punctuation
+func searchTerms(text string) []string {
+	terms := strings.Fields(normalizeAnswer(text))
+
+	slices.Sort(terms)
+
+	return slices.Compact(terms)
+}
+
+// triviaTerms returns the words a question can be found by, which exclude
+// its answers when they are checked by the server, so that they cannot be looked up
+func triviaTerms(t *Trivia) []string {
+	text := []string{t.Question, t.Category.String()}
+
+	if !checkAnswers {
+		text = append(text, t.Answer)
+		text = append(text, t.Alternatives...)
+	}
+
+	return searchTerms(strings.Join(text, " "))
+}
+
+// indexTerms adds a question to the search index, which is only built if --search is passed,
+// and must be called with the write lock held
+func (q *Questions) indexTerms(id QuestionId, t *Trivia) {
+	if !searchEnabled {
+		return
+	}
+
+	for _, term := range triviaTerms(t) {
+		ids, exists := q.terms[term]
+		if !exists {
+			ids = map[QuestionId]bool{}
+
+			q.terms[term] = ids
+		}
+
+		ids[id] = true
+	}
+}
+
+// unindexTerms removes a question from the search index, and must be called with the write lock held
+func (q *Questions) unindexTerms(id QuestionId, t *Trivia) {
+	if !searchEnabled {
+		return
+	}
+
+	for _, term := range triviaTerms(t) {
+		delete(q.terms[term], id)
+
+		if len(q.terms[term]) < 1 {
+			delete(q.terms, term)
+		}
+	}
+}
+
+// search returns the questions containing every word of the query, ordered by
+// category and question, along with whether more than the maximum were found
+func (q *Questions) search(query string) ([]SearchResult, bool) {
+	terms := searchTerms(query)
+	if len(terms) < 1 {
+		return nil, false
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	// Starting from the rarest term keeps the intersection small
+	slices.SortFunc(terms, func(a, b string) int {
+		return cmp.Compare(len(q.terms[a]), len(q.terms[b]))
+	})
+
+	results := []SearchResult{}
+
+	for id := range q.terms[terms[0]] {
+		matches := true
+
+		for _, term := range terms[1:] {
+			if !q.terms[term][id] {
+				matches = false
+
+				break
+			}
+		}
+
+		if matches {
+			t := q.list[id]
+
+			results = append(results, SearchResult{
+				Id:       id,
+				Question: t.Question,
+				Category: t.Category,
+			})
+		}
+	}
+
+	slices.SortFunc(results, func(a, b SearchResult) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(a.Question, b.Question), cmp.Compare(a.Id, b.Id))
+	})
+
+	if len(results) > maxSearchResults {
+		return results[:maxSearchResults], true
+	}
+
+	return results, false
+}
+
+func getSearchTemplate() string {
+	return `<!DOCTYPE html>
+<html lang="en-US">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <meta name="Description" content="A very basic trivia webapp." />
+    <title>Trivia v{{.Version}}</title>
+    <link rel="stylesheet" href="/css/{{.Theme}}.css" />
+    <link rel="stylesheet" href="/css/trivia.css" />
+    <link rel="apple-touch-icon" sizes="180x180" href="/favicons/apple-touch-icon.webp" />
+    <link rel="icon" type="image/webp" sizes="32x32" href="/favicons/favicon-32x32.webp" />
+    <link rel="icon" type="image/webp" sizes="16x16" href="/favicons/favicon-16x16.webp" />
+    <link rel="manifest" href="/favicons/site.webmanifest" crossorigin="use-credentials" />
+    <link rel="mask-icon" href="/favicons/safari-pinned-tab.svg" color="#5bbad5" />
+    <meta name="msapplication-TileColor" content="#da532c" />
+    <meta name="theme-color" content="#ffffff" />
+  </head>
+  <body>
+    <p id="settings-link"><a href="/">Back to homepage</a></p>
+    <div class="settings-container">
+      <div class="settings-wrapper">
+        <form id="search-form" method="get" action="/search">
+          <input id="search" name="q" type="search" autocomplete="off" placeholder="Search questions" value="{{.Query}}" autofocus />
+          <button class="settings-submit" type="submit">Search</button>
+        </form>
+        {{if .Query}}
+        <div class="settings-section">
+          {{if .Results}}
+          <ul id="search-results">
+            {{range .Results}}
+            <li><a href="/q/{{.Id}}">{{.Question}}</a> <span class="search-category">{{.Category}}</span></li>
+            {{end}}
+          </ul>
+          {{if .More}}<p id="hint">Only the first {{len .Results}} results are shown.</p>{{end}}
+          {{else}}
+          <p>No questions found.</p>
+          {{end}}
+        </div>
+        {{end}}
+      </div>
+    </div>
+  </body>
+</html>`
+}
+
+func wantsJson(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func serveSearch(questions *Questions, tpl *template.Template, errorChannel chan<- error) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		query := r.URL.Query().Get("q")
+
+		results, more := questions.search(query)
+
+		if wantsJson(r) {
+			if results == nil {
+				results = []SearchResult{}
+			}
+
+			writeJson(w, http.StatusOK, results, errorChannel)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
+
+		w.Header().Set("Content-Security-Policy", "default-src 'self';")
+
+		securityHeaders(w)
+
+		page := SearchPage{
+			Version: ReleaseVersion,
+			Theme:   getTheme(r),
+			Query:   query,
+			Results: make([]SearchLink, 0, len(results)),
+			More:    more,
+		}
+
+		for _, result := range results {
+			link := SearchLink{
+				Id:       result.Id,
+				Question: result.Question,
+				Category: result.Category,
+			}
+
+			if html || markdown {
+				link.Question = template.HTML(result.Question)
+			}
+
+			page.Results = append(page.Results, link)
+		}
+
+		err := tpl.Execute(w, page)
+		if err != nil {
+			errorChannel <- err
+		}
+	}
+}
+
+func registerSearch(mux *Router, questions *Questions, errorChannel chan<- error) {
+	template, err := template.New("search").Parse(getSearchTemplate())
+	if err != nil {
+		errorChannel <- err
+
+		return
+	}
+
+	mux.GET("/search", serveSearch(questions, template, errorChannel))
+}
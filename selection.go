@@ -0,0 +1,199 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Selection modes determine how getRandomId picks a question
+const (
+	// SelectQuestion picks uniformly across every eligible question
+	SelectQuestion string = "question"
+
+	// SelectCategory picks a category uniformly, then a question within it
+	SelectCategory string = "category"
+
+	// SelectWeighted picks a category according to its weight, then a question within it
+	SelectWeighted string = "weighted"
+)
+
+var selectionModes = []string{SelectQuestion, SelectCategory, SelectWeighted}
+
+// parseWeight returns the weight represented by the given string,
+// which must be a finite, non-negative number
+func parseWeight(s string) (float64, bool) {
+	weight, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return 0, false
+	}
+
+	return weight, true
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'g', -1, 64)
+}
+
+// getWeight returns the weight of a category, preferring the provided overrides
+// to the colors file, and must be called with the read lock held
+func (q *Questions) getWeight(category Category, weights map[string]float64) float64 {
+	weight, exists := weights[category.String()]
+	if exists {
+		return weight
+	}
+
+	return getColor(q.colors, category).Weight
+}
+
+// A Bucket holds the questions in a single category, grouped by difficulty so that
+// the questions of each difficulty are contiguous
+type Bucket struct {
+	ids []QuestionId
+
+	// Ends is the offset in ids at which the questions of each difficulty end,
+	// in the order of Difficulties
+	ends [len(Difficulties)]int
+}
+
+func newBucket(ids []QuestionId, list map[QuestionId]*Trivia) *Bucket {
+	b := &Bucket{
+		ids: make([]QuestionId, 0, len(ids)),
+	}
+
+	for i, d := range Difficulties {
+		for _, id := range ids {
+			if list[id].Difficulty == d {
+				b.ids = append(b.ids, id)
+			}
+		}
+
+		b.ends[i] = len(b.ids)
+	}
+
+	return b
+}
+
+// span returns the questions of the difficulty at index i of Difficulties
+func (b *Bucket) span(i int) []QuestionId {
+	start := 0
+
+	if i > 0 {
+		start = b.ends[i-1]
+	}
+
+	return b.ids[start:b.ends[i]]
+}
+
+// count returns the number of questions in the given difficulties
+func (b *Bucket) count(difficulties DifficultySet) int {
+	if difficulties == 0 {
+		return len(b.ids)
+	}
+
+	n := 0
+
+	for i, d := range Difficulties {
+		if difficulties.has(d) {
+			n += len(b.span(i))
+		}
+	}
+
+	return n
+}
+
+// nth returns the nth question in the given difficulties, which must be less than their count
+func (b *Bucket) nth(difficulties DifficultySet, n int) QuestionId {
+	if difficulties == 0 {
+		return b.ids[n]
+	}
+
+	for i, d := range Difficulties {
+		if !difficulties.has(d) {
+			continue
+		}
+
+		span := b.span(i)
+
+		if n < len(span) {
+			return span[n]
+		}
+
+		n -= len(span)
+	}
+
+	return NoQuestion
+}
+
+// share returns the relative likelihood of a category being picked under the given
+// selection mode, which is zero if it has no eligible questions, and must be called
+// with the read lock held
+func (q *Questions) share(category Category, count int, mode string, weights map[string]float64) float64 {
+	switch {
+	case count < 1:
+		return 0
+	case mode == SelectCategory:
+		return 1
+	case mode == SelectWeighted:
+		return q.getWeight(category, weights)
+	default:
+		return float64(count)
+	}
+}
+
+// pick returns a random question from the provided categories and difficulties, picking
+// a category according to the selection mode and then a question within it. Categories
+// not present in weights fall back to the colors file. It does not allocate, so that
+// the cost of a pick depends only on the number of categories.
+func (q *Questions) pick(categories []string, difficulties DifficultySet, mode string, weights map[string]float64) QuestionId {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	total := 0.0
+
+	for _, c := range categories {
+		b, exists := q.buckets[Category(c)]
+		if exists {
+			total += q.share(Category(c), b.count(difficulties), mode, weights)
+		}
+	}
+
+	if total <= 0 {
+		return NoQuestion
+	}
+
+	// Rounding can leave n just short of zero after the last eligible category, so it is picked by default
+	var picked *Bucket
+	var count int
+
+	n := rand.Float64() * total
+
+	for _, c := range categories {
+		b, exists := q.buckets[Category(c)]
+		if !exists {
+			continue
+		}
+
+		eligible := b.count(difficulties)
+
+		share := q.share(Category(c), eligible, mode, weights)
+		if share <= 0 {
+			continue
+		}
+
+		picked, count = b, eligible
+
+		if n < share {
+			break
+		}
+
+		n -= share
+	}
+
+	return picked.nth(difficulties, rand.IntN(count))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newBenchmarkQuestions loads 50 categories of 1,000 questions each, spread across every difficulty
+func newBenchmarkQuestions(b *testing.B) *Questions {
+	b.Helper()
+
+	var s strings.Builder
+
+	for c := range 50 {
+		for i := range 1000 {
+			fmt.Fprintf(&s, "Question %d?|Answer %d|Category %d", i, i, c)
+
+			if d := Difficulties[i%len(Difficulties)]; d != Unrated {
+				fmt.Fprintf(&s, "|difficulty=%s", d)
+			}
+
+			s.WriteString("\n")
+		}
+	}
+
+	path := filepath.Join(b.TempDir(), "benchmark.trivia")
+
+	err := os.WriteFile(path, []byte(s.String()), 0o644)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	errorChannel := make(chan error, 1)
+
+	q := newQuestions()
+
+	loadQuestions([]string{path}, q, nil, errorChannel)
+
+	select {
+	case err := <-errorChannel:
+		b.Fatal(err)
+	default:
+	}
+
+	return q
+}
+
+func BenchmarkPick(b *testing.B) {
+	q := newBenchmarkQuestions(b)
+
+	categories := q.CategoryStrings()
+
+	weights := map[string]float64{"Category 1": 2}
+
+	for _, mode := range selectionModes {
+		for _, difficulties := range []DifficultySet{0, parseDifficultySet("easy,hard")} {
+			name := difficulties.String()
+			if name == "" {
+				name = "all"
+			}
+
+			b.Run(mode+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+
+				for b.Loop() {
+					if q.pick(categories, difficulties, mode, weights) == NoQuestion {
+						b.Fatal("no question picked")
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGetRandomId(b *testing.B) {
+	q := newBenchmarkQuestions(b)
+
+	previousSettings, previousSelection := settings, selection
+	settings, selection = true, SelectQuestion
+
+	b.Cleanup(func() {
+		settings, selection = previousSettings, previousSelection
+	})
+
+	b.Run("without cookie", func(b *testing.B) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if q.getRandomId(r) == NoQuestion {
+				b.Fatal("no question picked")
+			}
+		}
+	})
+
+	// Parsing the Cookie header allocates within net/http, but expanding the categories is cached
+	b.Run("with cookie", func(b *testing.B) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: "enabledCategories", Value: "Category 1,Category 2,Category 3"})
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if q.getRandomId(r) == NoQuestion {
+				b.Fatal("no question picked")
+			}
+		}
+	})
+}
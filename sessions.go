@@ -0,0 +1,208 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookie   string        = "session"
+	sessionLifetime time.Duration = 24 * time.Hour
+
+	// The least recently used decks are discarded once either limit is exceeded,
+	// since clients can create sessions at will
+	maxDecks         int = 10000
+	maxDeckQuestions int = 1 << 22
+)
+
+// A Deck holds the questions not yet dealt to a session,
+// for the set of categories and difficulties it was shuffled from
+type Deck struct {
+	filter   string
+	ids      []QuestionId
+	last     QuestionId
+	lastUsed time.Time
+}
+
+type Sessions struct {
+	mu sync.Mutex
+
+	// Key is used to sign session cookies, and is regenerated on every start
+	key []byte
+
+	// Decks is a mapping of session identifiers to their decks
+	decks map[string]*Deck
+
+	// Held is the total capacity of every deck, as questions are dealt
+	// without shrinking the underlying arrays
+	held int
+
+	lastPruned time.Time
+}
+
+func newSessions() *Sessions {
+	return &Sessions{
+		key:        []byte(rand.Text()),
+		decks:      map[string]*Deck{},
+		lastPruned: time.Now(),
+	}
+}
+
+func (s *Sessions) sign(id string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// getSession returns the identifier from a validly-signed session cookie,
+// or issues a new session and returns false
+func (s *Sessions) getSession(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, signature, found := strings.Cut(getCookie(r, sessionCookie), ".")
+	if found && hmac.Equal([]byte(signature), []byte(s.sign(id))) {
+		return id, true
+	}
+
+	id = rand.Text()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id + "." + s.sign(id),
+		Path:     "/",
+		MaxAge:   int(sessionLifetime.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return id, false
+}
+
+// prune removes decks which have not been used within the session lifetime
+func (s *Sessions) prune() {
+	if time.Since(s.lastPruned) < time.Hour {
+		return
+	}
+
+	for id, deck := range s.decks {
+		if time.Since(deck.lastUsed) > sessionLifetime {
+			s.discard(id)
+		}
+	}
+
+	s.lastPruned = time.Now()
+}
+
+func (s *Sessions) discard(id string) {
+	s.held -= cap(s.decks[id].ids)
+
+	delete(s.decks, id)
+}
+
+// evict discards the least recently used decks other than the current one
+// until the number of decks and the questions they hold are within their limits
+func (s *Sessions) evict(current string) {
+	for len(s.decks) > maxDecks || s.held > maxDeckQuestions {
+		oldest := ""
+
+		for id, deck := range s.decks {
+			if id != current && (oldest == "" || deck.lastUsed.Before(s.decks[oldest].lastUsed)) {
+				oldest = id
+			}
+		}
+
+		if oldest == "" {
+			return
+		}
+
+		s.discard(oldest)
+	}
+}
+
+// next deals the next question from the deck belonging to the requesting session,
+// reshuffling every eligible question once the deck is exhausted
+func (s *Sessions) next(w http.ResponseWriter, r *http.Request, questions *Questions) QuestionId {
+	id, valid := s.getSession(w, r)
+
+	// Decks are only created once a client has returned a session cookie,
+	// so that cookieless clients cannot create an unbounded number of them
+	if !valid {
+		return questions.getRandomId(r)
+	}
+
+	categories := getCategories(r, questions)
+	difficulties := getDifficulties(r)
+	key := strings.Join(categories, ",") + "|" + difficulties.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	deck, exists := s.decks[id]
+	if !exists || deck.filter != key {
+		if exists {
+			s.discard(id)
+		}
+
+		deck = &Deck{filter: key}
+
+		s.decks[id] = deck
+
+		s.evict(id)
+	}
+
+	deck.lastUsed = time.Now()
+
+	shuffled := false
+
+	for {
+		if len(deck.ids) < 1 {
+			if shuffled {
+				return NoQuestion
+			}
+
+			s.held -= cap(deck.ids)
+
+			deck.ids = questions.getEligible(categories, difficulties)
+
+			s.held += cap(deck.ids)
+
+			s.evict(id)
+
+			mathrand.Shuffle(len(deck.ids), func(i, j int) {
+				deck.ids[i], deck.ids[j] = deck.ids[j], deck.ids[i]
+			})
+
+			// Avoid dealing the same question twice in a row across a reshuffle
+			if len(deck.ids) > 1 && deck.ids[len(deck.ids)-1] == deck.last {
+				deck.ids[0], deck.ids[len(deck.ids)-1] = deck.ids[len(deck.ids)-1], deck.ids[0]
+			}
+
+			shuffled = true
+
+			continue
+		}
+
+		next := deck.ids[len(deck.ids)-1]
+		deck.ids = deck.ids[:len(deck.ids)-1]
+
+		// Questions removed by a reload since the deck was shuffled are discarded
+		if questions.getTrivia(next) != nil {
+			deck.last = next
+
+			return next
+		}
+	}
+}
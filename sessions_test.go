@@ -0,0 +1,64 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionsEvictLeastRecentlyUsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "q.trivia")
+
+	err := os.WriteFile(path, []byte("Q1|A|C\nQ2|B|C\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := newQuestions()
+
+	load(t, q, []string{path})
+
+	s := newSessions()
+
+	deal := func(id string) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: sessionCookie, Value: id + "." + s.sign(id)})
+
+		if s.next(httptest.NewRecorder(), r, q) == NoQuestion {
+			t.Fatalf("no question dealt to session %s", id)
+		}
+	}
+
+	for i := range maxDecks + 10 {
+		deal(fmt.Sprintf("session-%d", i))
+	}
+
+	if len(s.decks) > maxDecks {
+		t.Errorf("%d decks held, want at most %d", len(s.decks), maxDecks)
+	}
+
+	if _, exists := s.decks["session-0"]; exists {
+		t.Error("least recently used deck was not evicted")
+	}
+
+	if _, exists := s.decks[fmt.Sprintf("session-%d", maxDecks+9)]; !exists {
+		t.Error("most recently used deck was evicted")
+	}
+
+	held := 0
+
+	for _, deck := range s.decks {
+		held += cap(deck.ids)
+	}
+
+	if held != s.held {
+		t.Errorf("held count is %d, want %d", s.held, held)
+	}
+}
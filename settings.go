@@ -9,22 +9,30 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
-	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
 type SelectedCategories struct {
-	Categories []string `json:"categories"`
+	Categories []string           `json:"categories"`
+	Weights    map[string]float64 `json:"weights"`
+}
+
+type SelectedDifficulties struct {
+	Difficulties []string `json:"difficulties"`
 }
 
 type CategoryToggle struct {
-	Version    string
-	Theme      string
-	Categories any
+	Version      string
+	Theme        string
+	Categories   any
+	Difficulties any
+	Selection    any
 }
 
 func getSettingsTemplate() string {
@@ -36,6 +44,8 @@ func getSettingsTemplate() string {
     <meta name="Description" content="A very basic trivia webapp." />
     <title>Trivia v{{.Version}}</title>
     <script src="/js/toggleCategories.js" defer></script>
+    <script src="/js/toggleDifficulties.js" defer></script>
+    <script src="/js/toggleSelection.js" defer></script>
 	<script src="/js/toggleTheme.js" defer></script>
     <link rel="stylesheet" href="/css/{{.Theme}}.css" />
 	<link rel="stylesheet" href="/css/trivia.css" />
@@ -59,7 +69,7 @@ func getSettingsTemplate() string {
   	  <div class="settings-wrapper">
         <div class="settings-section">
           <h2>Categories</h2>
-	      <ul>
+	      <ul id="categories">
 {{.Categories}}
           </ul>
 	    </div>
@@ -70,6 +80,27 @@ func getSettingsTemplate() string {
 	    <button id="set-categories" class="settings-submit">Submit</button>
       </div>
 
+      <div class="settings-wrapper">
+        <div class="settings-section">
+          <h2>Difficulty</h2>
+          <div id="difficulties" class="theme-options">
+{{.Difficulties}}
+          </div>
+        </div>
+        <button id="set-difficulties" class="settings-submit">Submit</button>
+      </div>
+
+      <div class="settings-wrapper">
+        <div class="settings-section">
+          <h2>Selection</h2>
+          <div id="selection" class="theme-options">
+{{.Selection}}
+          </div>
+          <p class="settings-hint">In weighted mode, categories are picked according to the weights entered next to them.</p>
+        </div>
+        <button id="set-selection" class="settings-submit">Submit</button>
+      </div>
+
       <div class="settings-wrapper">
  	    <div class="settings-section">
 	      <h2>Theme</h2>
@@ -91,6 +122,127 @@ func getSettingsTemplate() string {
 </html>`
 }
 
+// A CategoryNode is an entry in the category tree shown on the settings page,
+// which may exist only as the parent of categories containing questions
+type CategoryNode struct {
+	Path     Category
+	Loaded   bool
+	Children []*CategoryNode
+}
+
+// allEnabled reports whether every loaded category at or beneath the node is enabled
+func (n *CategoryNode) allEnabled(enabled map[string]bool) bool {
+	if n.Loaded && !enabled[n.Path.String()] {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if !child.allEnabled(enabled) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// anyEnabled reports whether any loaded category at or beneath the node is enabled
+func (n *CategoryNode) anyEnabled(enabled map[string]bool) bool {
+	if n.Loaded && enabled[n.Path.String()] {
+		return true
+	}
+
+	return slices.ContainsFunc(n.Children, func(child *CategoryNode) bool {
+		return child.anyEnabled(enabled)
+	})
+}
+
+// buildCategoryTree arranges the sorted list of categories into a tree,
+// adding any parent categories which contain no questions of their own
+func buildCategoryTree(categories []string) []*CategoryNode {
+	roots := []*CategoryNode{}
+
+	nodes := map[Category]*CategoryNode{}
+
+	var add func(path Category) *CategoryNode
+
+	add = func(path Category) *CategoryNode {
+		if n, exists := nodes[path]; exists {
+			return n
+		}
+
+		n := &CategoryNode{Path: path}
+
+		nodes[path] = n
+
+		if parent := path.parent(); parent != "" {
+			p := add(parent)
+			p.Children = append(p.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+
+		return n
+	}
+
+	for _, c := range categories {
+		add(Category(c)).Loaded = true
+	}
+
+	return roots
+}
+
+// writeCategoryTree writes a checkbox for each node, nesting the children of parent categories
+// in collapsible lists which start expanded if only some of their categories are enabled
+func writeCategoryTree(b *strings.Builder, nodes []*CategoryNode, depth int, enabled map[string]bool, weights map[string]float64, questions *Questions) {
+	indent := strings.Repeat("  ", depth*2)
+
+	for _, n := range nodes {
+		path := template.HTMLEscapeString(n.Path.String())
+		name := template.HTMLEscapeString(n.Path.name())
+
+		checked := ""
+
+		if n.allEnabled(enabled) {
+			checked = " checked"
+		}
+
+		// The weight from the colors file is shown as a placeholder, so that
+		// only weights which have been explicitly set are stored in the cookie
+		input := ""
+
+		if n.Loaded {
+			weight := ""
+
+			if w, exists := weights[n.Path.String()]; exists {
+				weight = formatWeight(w)
+			}
+
+			input = fmt.Sprintf("<input type=\"number\" class=\"category-weight\" name=\"%s\" min=\"0\" step=\"any\" placeholder=\"%s\" value=\"%s\">",
+				path, formatWeight(questions.getColor(n.Path).Weight), weight)
+		}
+
+		if len(n.Children) == 0 {
+			fmt.Fprintf(b, "            %s<li><label><input type=\"checkbox\" name=\"%s\"%s>%s</label>%s</li>\n", indent, path, checked, name, input)
+
+			continue
+		}
+
+		open := ""
+
+		if checked == "" && n.anyEnabled(enabled) {
+			open = " open"
+		}
+
+		fmt.Fprintf(b, "            %s<li><input type=\"checkbox\" name=\"%s\"%s><details%s><summary>%s</summary>%s\n", indent, path, checked, open, name, input)
+		fmt.Fprintf(b, "            %s  <ul>\n", indent)
+
+		writeCategoryTree(b, n.Children, depth+1, enabled, weights, questions)
+
+		fmt.Fprintf(b, "            %s  </ul>\n", indent)
+		fmt.Fprintf(b, "            %s</details></li>\n", indent)
+	}
+}
+
 func serveSettingsPage(questions *Questions, tpl *template.Template, errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		w.Header().Set("Content-Type", "text/html;charset=UTF-8")
@@ -101,20 +253,56 @@ func serveSettingsPage(questions *Questions, tpl *template.Template, errorChanne
 
 		var toggles strings.Builder
 
-		selected := getCategories(r, questions)
+		selected := map[string]bool{}
+
+		for _, c := range getCategories(r, questions) {
+			selected[c] = true
+		}
+
+		writeCategoryTree(&toggles, buildCategoryTree(questions.CategoryStrings()), 0, selected, getWeights(r), questions)
+
+		var difficulties strings.Builder
+
+		enabled := getDifficulties(r)
+
+		for _, d := range Difficulties {
+			checked := ""
 
-		for _, j := range questions.CategoryStrings() {
-			if slices.Contains(selected, j) {
-				toggles.WriteString(fmt.Sprintf("            <li><label><input type=\"checkbox\" name=\"%s\" checked>%s</label></li>\n", j, j))
-			} else {
-				toggles.WriteString(fmt.Sprintf("            <li><label><input type=\"checkbox\" name=\"%s\">%s</label></li>\n", j, j))
+			if enabled.has(d) {
+				checked = " checked"
 			}
+
+			label := strings.ToUpper(d.String()[:1]) + d.String()[1:]
+
+			difficulties.WriteString(fmt.Sprintf("            <label><input type=\"checkbox\" name=\"%s\"%s>%s</label>\n", d, checked, label))
+		}
+
+		var modes strings.Builder
+
+		mode := getSelection(r)
+
+		for _, m := range selectionModes {
+			checked := ""
+
+			if m == mode {
+				checked = " checked"
+			}
+
+			label := map[string]string{
+				SelectQuestion: "Any question",
+				SelectCategory: "Category first",
+				SelectWeighted: "Weighted categories",
+			}[m]
+
+			modes.WriteString(fmt.Sprintf("            <label><input type=\"radio\" name=\"selection\" value=\"%s\"%s>%s</label>\n", m, checked, label))
 		}
 
 		categoryToggle := CategoryToggle{
-			Version:    ReleaseVersion,
-			Theme:      getTheme(r),
-			Categories: template.HTML(toggles.String()),
+			Version:      ReleaseVersion,
+			Theme:        getTheme(r),
+			Categories:   template.HTML(toggles.String()),
+			Difficulties: template.HTML(difficulties.String()),
+			Selection:    template.HTML(modes.String()),
 		}
 
 		err := tpl.Execute(w, categoryToggle)
@@ -126,8 +314,6 @@ func serveSettingsPage(questions *Questions, tpl *template.Template, errorChanne
 
 func serveCategorySettings(questions *Questions, errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
-
 		data, err := io.ReadAll(r.Body)
 		if err != nil {
 			errorChannel <- err
@@ -147,43 +333,90 @@ func serveCategorySettings(questions *Questions, errorChannel chan<- error) http
 
 		c := []string{}
 
+		// Parent categories are kept, so that categories added beneath them later are also enabled
 		for _, s := range selected.Categories {
-			for _, e := range enabled {
-				if s == e {
-					c = append(c, s)
-				}
+			if slices.ContainsFunc(enabled, func(e string) bool {
+				return Category(s).contains(Category(e))
+			}) && !slices.Contains(c, s) {
+				c = append(c, s)
 			}
 		}
 
 		setCookie("enabledCategories", strings.Join(c, ","), w)
 
-		if verbose {
-			fmt.Printf("%s | %s => %s (Selected %d/%d categories)\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI,
-				len(c),
-				len(enabled))
+		weights := url.Values{}
+
+		for category, weight := range selected.Weights {
+			if slices.Contains(enabled, category) && weight >= 0 {
+				weights.Set(category, formatWeight(weight))
+			}
 		}
+
+		setCookie("categoryWeights", weights.Encode(), w)
+
+		slog.Debug("Selected categories",
+			"remote", realIP(r),
+			"selected", len(c),
+			"weighted", len(weights),
+			"total", len(enabled))
 	}
 }
 
-func serveThemeSettings() httprouter.Handle {
+func serveDifficultySettings(errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			errorChannel <- err
 
-		setCookie("colorTheme", p.ByName("theme"), w)
+			return
+		}
+
+		var selected SelectedDifficulties
+		err = json.Unmarshal(data, &selected)
+		if err != nil {
+			errorChannel <- err
+
+			return
+		}
+
+		d := parseDifficultySet(strings.Join(selected.Difficulties, ","))
+
+		// The empty set enables every difficulty, so it cannot be stored
+		if d == 0 || d == noDifficulties {
+			http.Error(w, "At least one difficulty must be selected", http.StatusBadRequest)
+
+			return
+		}
+
+		setCookie("enabledDifficulties", d.String(), w)
 
-		if verbose {
-			fmt.Printf("%s | %s => %s\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI)
+		slog.Debug("Selected difficulties",
+			"remote", realIP(r),
+			"selected", d.String())
+	}
+}
+
+func serveSelectionSettings() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		mode := p.ByName("mode")
+
+		if !slices.Contains(selectionModes, mode) {
+			http.Error(w, fmt.Sprintf("Unsupported selection mode %q", mode), http.StatusBadRequest)
+
+			return
 		}
+
+		setCookie("selectionMode", mode, w)
+	}
+}
+
+func serveThemeSettings() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		setCookie("colorTheme", p.ByName("theme"), w)
 	}
 }
 
-func registerSettingsPage(mux *httprouter.Router, questions *Questions, errorChannel chan<- error) {
+func registerSettingsPage(mux *Router, questions *Questions, errorChannel chan<- error) {
 	template, err := template.New("settings").Parse(getSettingsTemplate())
 	if err != nil {
 		errorChannel <- err
@@ -193,5 +426,7 @@ func registerSettingsPage(mux *httprouter.Router, questions *Questions, errorCha
 
 	mux.GET("/settings", serveSettingsPage(questions, template, errorChannel))
 	mux.POST("/settings/categories", serveCategorySettings(questions, errorChannel))
+	mux.POST("/settings/difficulties", serveDifficultySettings(errorChannel))
+	mux.POST("/settings/selection/:mode", serveSelectionSettings())
 	mux.POST("/settings/theme/:theme", serveThemeSettings())
 }
@@ -0,0 +1,42 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// registerShutdown gracefully shuts down the server once SIGINT or SIGTERM is received,
+// closing quit so that background rebuilds stop, and waiting up to the given timeout
+// for in-flight requests to finish. The returned channel receives the result of the shutdown.
+func registerShutdown(srv *http.Server, quit chan<- struct{}, timeout time.Duration) <-chan error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan error, 1)
+
+	go func() {
+		<-ctx.Done()
+
+		// Restore the default behaviour, so that a second signal exits immediately
+		stop()
+
+		slog.Info("Shutting down", "timeout", timeout)
+
+		close(quit)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		done <- srv.Shutdown(shutdownCtx)
+	}()
+
+	return done
+}
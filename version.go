@@ -8,15 +8,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
 func serveVersion(errorChannel chan<- error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-		startTime := time.Now()
-
 		data := fmt.Appendf(nil, "trivia v%s\n", ReleaseVersion)
 
 		w.Header().Add("Content-Security-Policy", "default-src 'self';")
@@ -33,12 +30,5 @@ func serveVersion(errorChannel chan<- error) httprouter.Handle {
 
 			return
 		}
-
-		if verbose {
-			fmt.Printf("%s | %s => %s\n",
-				startTime.Format(logDate),
-				realIP(r),
-				r.RequestURI)
-		}
 	}
 }
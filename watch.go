@@ -0,0 +1,193 @@
+/*
+Copyright © 2026 Seednode <seednode@seedno.de>
+*/
+
+package main
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// Delay after the last filesystem event before rebuilding,
+	// so that a burst of changes only triggers a single rebuild
+	watchDelay time.Duration = 1 * time.Second
+)
+
+type Watcher struct {
+	*fsnotify.Watcher
+
+	// Files is the set of question files passed directly as arguments,
+	// plus the colors file, whose parent directories are watched on their behalf
+	files map[string]bool
+
+	// Parents is the set of directories containing a question file
+	// passed as an argument
+	parents map[string]bool
+
+	// Dirs is the set of directories watched for all question files
+	dirs map[string]bool
+}
+
+func (w *Watcher) addPath(path string, errorChannel chan<- error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		errorChannel <- err
+
+		return
+	}
+
+	if !info.IsDir() {
+		w.files[path] = true
+		w.parents[filepath.Dir(path)] = true
+
+		err = w.Add(filepath.Dir(path))
+		if err != nil {
+			errorChannel <- err
+		}
+
+		return
+	}
+
+	w.addDir(path, errorChannel)
+}
+
+func (w *Watcher) addDir(path string, errorChannel chan<- error) {
+	if !recursive {
+		w.dirs[path] = true
+
+		err := w.Add(path)
+		if err != nil {
+			errorChannel <- err
+		}
+
+		return
+	}
+
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			w.dirs[p] = true
+
+			return w.Add(p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		errorChannel <- err
+	}
+}
+
+// isRelevant reports whether an event should trigger a rebuild,
+// adding watches for any newly-created subdirectories along the way
+func (w *Watcher) isRelevant(event fsnotify.Event, errorChannel chan<- error) bool {
+	dir := filepath.Dir(event.Name)
+
+	if w.files[event.Name] {
+		return true
+	}
+
+	if w.parents[dir] && !w.dirs[dir] {
+		return false
+	}
+
+	if event.Has(fsnotify.Create) {
+		info, err := os.Stat(event.Name)
+		if err == nil && info.IsDir() {
+			if !recursive {
+				return false
+			}
+
+			w.addDir(event.Name, errorChannel)
+
+			return true
+		}
+	}
+
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		if w.dirs[event.Name] {
+			delete(w.dirs, event.Name)
+
+			return true
+		}
+	}
+
+	return !event.Has(fsnotify.Chmod) && matchesExtension(event.Name)
+}
+
+func registerWatcher(paths []string, questions *Questions, metrics *Metrics, quit <-chan struct{}, errorChannel chan<- error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		errorChannel <- err
+
+		return
+	}
+
+	watcher := &Watcher{
+		Watcher: fsWatcher,
+		files:   map[string]bool{},
+		parents: map[string]bool{},
+		dirs:    map[string]bool{},
+	}
+
+	for _, path := range paths {
+		watcher.addPath(path, errorChannel)
+	}
+
+	if colorsFile != "" {
+		watcher.addPath(filepath.Clean(colorsFile), errorChannel)
+	}
+
+	slog.Debug("Watching for changes", "directories", len(watcher.WatchList()))
+
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if !watcher.isRelevant(event, errorChannel) {
+					continue
+				}
+
+				slog.Debug("Detected change", "path", event.Name)
+
+				timer.Reset(watchDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				errorChannel <- err
+			case <-timer.C:
+				slog.Debug("Started watch-triggered rebuild")
+
+				loadQuestions(paths, questions, metrics, errorChannel)
+			case <-quit:
+				timer.Stop()
+
+				err := watcher.Close()
+				if err != nil {
+					errorChannel <- err
+				}
+
+				return
+			}
+		}
+	}()
+}
@@ -5,12 +5,13 @@ Copyright © 2026 Seednode <seednode@seedno.de>
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,58 @@ const (
 	logDate string = `2006-01-02T15:04:05.000-07:00`
 )
 
+// Router wraps httprouter so that every route is logged and instrumented as it is registered
+type Router struct {
+	*httprouter.Router
+
+	metrics *Metrics
+}
+
+func newRouter(metrics *Metrics) *Router {
+	mux := &Router{
+		Router:  httprouter.New(),
+		metrics: metrics,
+	}
+
+	if metrics != nil {
+		mux.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metrics.routeNotFound()
+
+			http.NotFound(w, r)
+		})
+	}
+
+	return mux
+}
+
+func (r *Router) GET(path string, h httprouter.Handle) {
+	r.Handle(http.MethodGet, path, h)
+}
+
+func (r *Router) POST(path string, h httprouter.Handle) {
+	r.Handle(http.MethodPost, path, h)
+}
+
+func (r *Router) Handle(method, path string, h httprouter.Handle) {
+	route := Route{method, path}
+
+	r.Router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		logRequest(route, w, req, func(w http.ResponseWriter) {
+			h(w, req, p)
+		}, r.metrics)
+	})
+}
+
+func (r *Router) Handler(method, path string, h http.Handler) {
+	route := Route{method, path}
+
+	r.Router.Handler(method, path, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		logRequest(route, w, req, func(w http.ResponseWriter) {
+			h.ServeHTTP(w, req)
+		}, r.metrics)
+	}))
+}
+
 func securityHeaders(w http.ResponseWriter) {
 	w.Header().Set("Cross-Origin-Embedder-Policy", "require-corp")
 	w.Header().Set("Cross-Origin-Opener-Policy", "same-origin")
@@ -68,12 +121,10 @@ func realIP(r *http.Request) string {
 }
 
 func serverError(w http.ResponseWriter, r *http.Request, i any) {
-	if verbose {
-		fmt.Printf("%s | %s => %s (Invalid request)\n",
-			time.Now().Format(logDate),
-			realIP(r),
-			r.RequestURI)
-	}
+	slog.Error("Recovered from panic",
+		"uri", r.RequestURI,
+		"remote", realIP(r),
+		"error", i)
 
 	w.WriteHeader(http.StatusInternalServerError)
 	w.Header().Add("Content-Type", "text/plain")
@@ -96,21 +147,47 @@ func servePage(args []string) error {
 		}
 	}
 
-	fmt.Printf("%s | trivia v%s\n",
-		time.Now().Format(logDate),
-		ReleaseVersion)
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(logger)
+
+	slog.Info("Starting trivia", "version", ReleaseVersion)
 
 	paths, err := validatePaths(args)
 	if err != nil {
 		return err
 	}
 
+	timeout, err := time.ParseDuration(shutdownTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid shutdown timeout: %w", err)
+	}
+
 	bindAddr := net.ParseIP(bind)
 	if bindAddr == nil {
 		return errors.New("invalid bind address provided")
 	}
 
-	mux := httprouter.New()
+	auth, err := newAuth()
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	var metrics *Metrics
+
+	if metricsEnabled {
+		metrics = newMetrics()
+	}
+
+	mux := newRouter(metrics)
 
 	mux.PanicHandler = serverErrorHandler()
 
@@ -120,29 +197,44 @@ func servePage(args []string) error {
 		IdleTimeout:  1 * time.Minute,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 5 * time.Second,
+		TLSConfig:    tlsConfig,
 	}
 
+	// Long-lived requests, such as room event streams, end once shutdown begins,
+	// rather than holding it open until the timeout expires
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv.BaseContext = func(net.Listener) context.Context {
+		return baseCtx
+	}
+
+	srv.RegisterOnShutdown(cancel)
+
+	quit := make(chan struct{})
+
+	shutdown := registerShutdown(srv, quit, timeout)
+
 	errorChannel := make(chan error)
 
 	go func() {
 		for err := range errorChannel {
+			metrics.error()
+
 			switch {
 			case exitOnError:
-				fmt.Printf("%s | FATAL: %v\n", time.Now().Format(logDate), err)
+				slog.Error("Fatal error", "error", err)
 			case errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission):
 				continue
 			default:
-				fmt.Printf("%s | ERROR: %v\n", time.Now().Format(logDate), err)
+				slog.Error("Encountered error", "error", err)
 			}
 		}
 	}()
 
-	questions := &Questions{
-		index: map[Category][]QuestionId{},
-		list:  map[QuestionId]*Trivia{},
-	}
+	questions := newQuestions()
 
-	loadQuestions(paths, questions, errorChannel)
+	loadQuestions(paths, questions, metrics, errorChannel)
 
 	registerFavicons(mux, errorChannel)
 
@@ -151,46 +243,65 @@ func servePage(args []string) error {
 	registerJs(mux, errorChannel)
 
 	if export {
-		registerExport(mux, questions, errorChannel)
+		registerExport(mux, auth, questions, errorChannel)
+	}
+
+	if metricsEnabled {
+		registerMetrics(mux, auth, metrics, questions, errorChannel)
 	}
 
 	if profile {
-		registerProfile(mux)
+		registerProfile(mux, auth)
 	}
 
 	if reload {
-		registerReload(mux, paths, questions, errorChannel)
+		registerReload(mux, auth, paths, questions, metrics, errorChannel)
 	}
 
-	if reloadInterval != "" {
-		quit := make(chan struct{})
-		defer close(quit)
+	registerHangup(paths, questions, metrics, quit, errorChannel)
 
-		registerReloadInterval(paths, questions, quit, errorChannel)
+	if reloadInterval != "" {
+		registerReloadInterval(paths, questions, metrics, quit, errorChannel)
 	}
 
-	validColor := regexp.MustCompile(ValidHexColor)
-
-	colors := loadColors(colorsFile, validColor, errorChannel)
+	if watch {
+		registerWatcher(paths, questions, metrics, quit, errorChannel)
+	}
 
 	if settings {
 		registerSettingsPage(mux, questions, errorChannel)
 	}
 
-	registerQuestions(mux, colors, questions, errorChannel)
+	var sessions *Sessions
+
+	if noRepeat {
+		sessions = newSessions()
+	}
+
+	registerQuestions(mux, questions, sessions, metrics, errorChannel)
+
+	registerMedia(mux, paths, questions, errorChannel)
+
+	if api {
+		registerApi(mux, questions, metrics, errorChannel)
+	}
+
+	if searchEnabled {
+		registerSearch(mux, questions, errorChannel)
+	}
+
+	if roomsEnabled {
+		registerRooms(mux, questions, metrics, errorChannel)
+	}
 
 	mux.GET("/version", serveVersion(errorChannel))
 
 	if tlsKey != "" && tlsCert != "" {
-		fmt.Printf("%s | Listening on https://%s/\n",
-			time.Now().Format(logDate),
-			srv.Addr)
+		slog.Info("Listening", "url", "https://"+srv.Addr+"/")
 
 		err = srv.ListenAndServeTLS(tlsCert, tlsKey)
 	} else {
-		fmt.Printf("%s | Listening on http://%s/\n",
-			time.Now().Format(logDate),
-			srv.Addr)
+		slog.Info("Listening", "url", "http://"+srv.Addr+"/")
 
 		err = srv.ListenAndServe()
 	}
@@ -199,5 +310,10 @@ func servePage(args []string) error {
 		return err
 	}
 
-	return nil
+	err = <-shutdown
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.New("timed out waiting for in-flight requests to finish")
+	}
+
+	return err
 }